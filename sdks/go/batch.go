@@ -0,0 +1,104 @@
+package pulsekit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxRequeues bounds how many times an event rejected in a partial batch
+// failure is retried before it is dropped.
+const maxRequeues = 3
+
+// batchResponse is the body of a 207 Multi-Status reply from the batch
+// endpoint. Indices refer to positions in the submitted events array.
+type batchResponse struct {
+	Accepted []int `json:"accepted"`
+	Rejected []int `json:"rejected"`
+}
+
+// errRejected reports events the server rejected in partially failed
+// batches until they were dropped.
+var errRejected = errors.New("rejected by the server")
+
+// handlePartialFailure parses a 207 response and re-queues only the events
+// the server rejected. It returns how many rejected events were dropped
+// instead because they had already been re-queued maxRequeues times.
+func (c *Client) handlePartialFailure(events []Event, body io.Reader) int {
+	var result batchResponse
+	if err := json.NewDecoder(body).Decode(&result); err != nil {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Failed to parse batch response: %v\n", err)
+		}
+		return 0
+	}
+
+	var retry []Event
+	dropped := 0
+	for _, i := range result.Rejected {
+		if i < 0 || i >= len(events) {
+			continue
+		}
+		event := events[i]
+		if event.requeues >= maxRequeues {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Dropping %q event after %d rejected attempts\n", event.Type, event.requeues+1)
+			}
+			c.drop(event, DropReasonRejected)
+			dropped++
+			continue
+		}
+		event.requeues++
+		retry = append(retry, event)
+	}
+
+	if c.config.Debug {
+		fmt.Printf("[PulseKit] Batch partially accepted: %d accepted, %d rejected, %d re-queued\n",
+			len(result.Accepted), len(result.Rejected), len(retry))
+	}
+	c.requeue(retry)
+	return dropped
+}
+
+// requeue puts events back at the front of the queue so they are sent with
+// the next flush.
+func (c *Client) requeue(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+	c.mu.Lock()
+	evicted := c.pushFrontLocked(events...)
+	c.mu.Unlock()
+	c.reportEvicted(evicted)
+}
+
+// drainRequeued resends the events a partial batch failure re-queued during
+// the final flush, which no flush loop is left to send. Each round either
+// delivers an event, re-queues it once more or drops it, so maxRequeues
+// rounds leave none behind. Anything still queued after that is dropped.
+// The returned error counts every event that was not delivered.
+func (c *Client) drainRequeued(ctx context.Context) error {
+	var result sendResult
+	for i := 0; i < maxRequeues; i++ {
+		c.mu.Lock()
+		events := c.popLocked(-1)
+		c.mu.Unlock()
+		if len(events) == 0 {
+			return result.error()
+		}
+		result.add(c.sendEvents(ctx, events))
+	}
+
+	c.mu.Lock()
+	events := c.popLocked(-1)
+	c.mu.Unlock()
+	for _, event := range events {
+		c.drop(event, DropReasonRejected)
+	}
+	if len(events) > 0 {
+		result.add(sendResult{undelivered: len(events), err: errRejected})
+	}
+	return result.error()
+}
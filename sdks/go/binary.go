@@ -0,0 +1,41 @@
+package pulsekit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+// binaryEncoding is the encoding hint sent alongside binary metadata values.
+const binaryEncoding = "base64"
+
+// Binary is a metadata value holding raw bytes. It is encoded as an object
+// carrying the base64 data and an encoding hint so the server knows to decode
+// it. []byte metadata values are converted to Binary automatically.
+type Binary []byte
+
+// MarshalJSON encodes b as {"encoding": "base64", "data": "..."}.
+func (b Binary) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Encoding string `json:"encoding"`
+		Data     string `json:"data"`
+	}{binaryEncoding, base64.StdEncoding.EncodeToString(b)})
+}
+
+// encodeBinaryMetadata wraps []byte metadata values in Binary. The caller's
+// map is left untouched.
+func encodeBinaryMetadata(event *Event) {
+	var metadata map[string]interface{}
+	for k, v := range event.Metadata {
+		b, ok := v.([]byte)
+		if !ok {
+			continue
+		}
+		if metadata == nil {
+			metadata = copyMap(event.Metadata)
+		}
+		metadata[k] = Binary(b)
+	}
+	if metadata != nil {
+		event.Metadata = metadata
+	}
+}
@@ -0,0 +1,84 @@
+package pulsekit
+
+import (
+	"fmt"
+	"sync"
+)
+
+// highCardinality replaces tag values beyond Config.MaxTagCardinality.
+const highCardinality = "[high-cardinality]"
+
+// tagCardinality tracks the distinct values seen for each automatically
+// attached tag key.
+type tagCardinality struct {
+	mu        sync.Mutex
+	values    map[string]map[string]struct{}
+	offending map[string]bool
+}
+
+// limitTagCardinality returns tags with values beyond the first
+// Config.MaxTagCardinality distinct values seen for their key replaced by
+// "[high-cardinality]". The keys over the cap are reported by
+// HighCardinalityTags.
+func (c *Client) limitTagCardinality(tags map[string]string) map[string]string {
+	max := c.config.MaxTagCardinality
+	if max <= 0 {
+		return tags
+	}
+
+	t := &c.tagValues
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.values == nil {
+		t.values = make(map[string]map[string]struct{})
+		t.offending = make(map[string]bool)
+	}
+	for key, value := range tags {
+		seen := t.values[key]
+		if seen == nil {
+			seen = make(map[string]struct{})
+			t.values[key] = seen
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		if len(seen) < max {
+			seen[value] = struct{}{}
+			continue
+		}
+		if !t.offending[key] {
+			t.offending[key] = true
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Tag %q exceeded %d distinct values, reporting further values as %s\n", key, max, highCardinality)
+			}
+		}
+		tags[key] = highCardinality
+	}
+	return tags
+}
+
+func (t *tagCardinality) clear() {
+	t.mu.Lock()
+	t.values = nil
+	t.offending = nil
+	t.mu.Unlock()
+}
+
+// HighCardinalityTags returns the tag keys of the default client that have
+// exceeded Config.MaxTagCardinality.
+func HighCardinalityTags() []string {
+	client := getDefaultClient()
+	if client == nil {
+		return nil
+	}
+	return client.HighCardinalityTags()
+}
+
+// HighCardinalityTags returns, sorted, the tag keys that have exceeded
+// Config.MaxTagCardinality, usually a sign of a misconfigured
+// Config.RoutePatternFunc.
+func (c *Client) HighCardinalityTags() []string {
+	c.tagValues.mu.Lock()
+	defer c.tagValues.mu.Unlock()
+	return sortedKeys(c.tagValues.offending)
+}
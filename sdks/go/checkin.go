@@ -0,0 +1,164 @@
+package pulsekit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// CheckInStatus is the state reported by a cron or heartbeat check-in.
+type CheckInStatus string
+
+const (
+	CheckInInProgress CheckInStatus = "in_progress"
+	CheckInOK         CheckInStatus = "ok"
+	CheckInError      CheckInStatus = "error"
+)
+
+// CheckIn reports the status of a scheduled job to a PulseKit monitor.
+type CheckIn struct {
+	ID          string        `json:"id"`
+	MonitorSlug string        `json:"monitor_slug"`
+	Status      CheckInStatus `json:"status"`
+	DurationMs  float64       `json:"duration_ms,omitempty"`
+	Timestamp   string        `json:"timestamp,omitempty"`
+	Environment string        `json:"environment,omitempty"`
+	Release     string        `json:"release,omitempty"`
+	// Project routes the check-in to one of Config.Projects, like
+	// Event.Project. "" means the default project.
+	Project string `json:"-"`
+}
+
+// CheckInOption is a function that modifies a check-in.
+type CheckInOption func(*CheckIn)
+
+// WithCheckInID reuses the ID returned by an earlier in-progress check-in so
+// the server can pair the start and finish of a job run.
+func WithCheckInID(id string) CheckInOption {
+	return func(ci *CheckIn) {
+		ci.ID = id
+	}
+}
+
+// WithCheckInDuration records how long the job run took.
+func WithCheckInDuration(d time.Duration) CheckInOption {
+	return func(ci *CheckIn) {
+		ci.DurationMs = durationMs(d)
+	}
+}
+
+// WithCheckInProject sends the check-in to project, one of Config.Projects.
+func WithCheckInProject(project string) CheckInOption {
+	return func(ci *CheckIn) {
+		ci.Project = project
+	}
+}
+
+// CaptureCheckIn queues a check-in on the default client and returns its ID.
+// It returns "" if the client is not initialized.
+func CaptureCheckIn(monitorSlug string, status CheckInStatus, opts ...CheckInOption) string {
+	client := getDefaultClient()
+	if client == nil {
+		return ""
+	}
+	return client.CaptureCheckIn(monitorSlug, status, opts...)
+}
+
+// CaptureCheckIn queues a check-in for the monitor identified by monitorSlug
+// and returns its ID. Pass the ID to the finishing check-in via
+// WithCheckInID:
+//
+//	id := client.CaptureCheckIn("nightly-report", pulsekit.CheckInInProgress)
+//	// ... run the job ...
+//	client.CaptureCheckIn("nightly-report", pulsekit.CheckInOK, pulsekit.WithCheckInID(id))
+//
+// Check-ins are delivered with the next flush, alongside queued events.
+func (c *Client) CaptureCheckIn(monitorSlug string, status CheckInStatus, opts ...CheckInOption) string {
+	ci := CheckIn{
+		MonitorSlug: monitorSlug,
+		Status:      status,
+	}
+	for _, opt := range opts {
+		opt(&ci)
+	}
+	if ci.ID == "" {
+		ci.ID = newID()
+	}
+	ci.Timestamp = c.timestamp()
+	ci.Environment = c.config.Environment
+	ci.Release = c.config.Release
+
+	c.mu.Lock()
+	c.checkIns = append(c.checkIns, ci)
+	shouldFlush := c.shouldFlushLocked()
+	c.mu.Unlock()
+
+	if shouldFlush || c.config.SynchronousMode {
+		c.Flush()
+	}
+	return ci.ID
+}
+
+// flushCheckIns sends all queued check-ins, one request per project.
+// Check-ins whose project has no API key are not sent and are reported in
+// the returned error.
+func (c *Client) flushCheckIns(ctx context.Context) error {
+	c.mu.Lock()
+	checkIns := c.checkIns
+	c.checkIns = nil
+	c.mu.Unlock()
+
+	if len(checkIns) == 0 {
+		return nil
+	}
+
+	var keys []string
+	byKey := make(map[string][]CheckIn)
+	unroutable := 0
+	for _, ci := range checkIns {
+		key, ok := c.apiKeyFor(ci.Project)
+		if !ok && !c.config.DryRun {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Dropping check-in for monitor %q: no API key for project %q\n", ci.MonitorSlug, ci.Project)
+			}
+			unroutable++
+			continue
+		}
+		if _, seen := byKey[key]; !seen {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], ci)
+	}
+
+	var errs []error
+	if unroutable > 0 {
+		errs = append(errs, fmt.Errorf("%d check-in(s) have no API key for their project", unroutable))
+	}
+	url := c.config.Endpoint + "/api/v1/checkins"
+	for _, key := range keys {
+		batch := byKey[key]
+		body := map[string]interface{}{"checkins": batch}
+		err := c.post(ctx, url, key, body, "check-ins", func(resp *http.Response) {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Sent %d check-in(s), status: %d\n", len(batch), resp.StatusCode)
+			}
+		})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newID returns a random 128-bit identifier in hex.
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,41 @@
+package pulsekit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCheckInProjects checks that check-ins are sent with their project's
+// key and that, without a default APIKey, check-ins that resolve no key are
+// reported instead of being sent unauthenticated.
+func TestCheckInProjects(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get("X-PulseKit-Key"))
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(Config{
+		Endpoint: ts.URL,
+		Projects: map[string]string{"billing": "billing-key"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CaptureCheckIn("invoices", CheckInOK, WithCheckInProject("billing"))
+	c.CaptureCheckIn("reports", CheckInOK)
+
+	err = c.Close()
+	if err == nil || !strings.Contains(err.Error(), "1 check-in(s) have no API key") {
+		t.Errorf("Close() = %v, want an error for the unroutable check-in", err)
+	}
+	if len(keys) != 1 || keys[0] != "billing-key" {
+		t.Errorf("requests sent with keys %q, want only %q", keys, "billing-key")
+	}
+}
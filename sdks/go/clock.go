@@ -0,0 +1,125 @@
+package pulsekit
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time so that time-dependent behavior (timestamps, flush
+// intervals) can be driven deterministically in tests.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTicker returns a ticker that fires every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker is the subset of *time.Ticker used by the client.
+type Ticker interface {
+	// C returns the channel on which ticks are delivered.
+	C() <-chan time.Time
+	// Stop turns off the ticker.
+	Stop()
+}
+
+// realClock is the default Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	t *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.t.C }
+func (t realTicker) Stop()               { t.t.Stop() }
+
+// FakeClock is a manually advanced Clock for tests. Its tickers fire only
+// when Advance moves the clock past their next deadline.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// NewFakeClock creates a FakeClock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the fake current time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker returns a ticker driven by Advance.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{
+		ch:       make(chan time.Time, 1),
+		interval: d,
+		next:     c.now.Add(d),
+	}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing any tickers whose deadline
+// has passed. Like time.Ticker, ticks are dropped if the receiver is behind.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		t.fire(c.now)
+	}
+}
+
+type fakeTicker struct {
+	mu       sync.Mutex
+	ch       chan time.Time
+	interval time.Duration
+	next     time.Time
+	stopped  bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() {
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+}
+
+func (t *fakeTicker) fire(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || now.Before(t.next) {
+		return
+	}
+	for !now.Before(t.next) {
+		t.next = t.next.Add(t.interval)
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+}
+
+// Timestamp layouts with fixed sub-second precision for Config.TimestampFormat.
+const (
+	TimestampMillis = "2006-01-02T15:04:05.000Z07:00"
+	TimestampMicros = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+// timestamp returns the current time formatted with Config.TimestampFormat.
+func (c *Client) timestamp() string {
+	return c.config.Clock.Now().UTC().Format(c.config.TimestampFormat)
+}
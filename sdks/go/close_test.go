@@ -0,0 +1,70 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestCloseDrainsRequeuedEvents checks that events rejected by a 207 on the
+// final flush are retried before Close returns, then dropped and reported
+// as undelivered once they exhaust their retries.
+func TestCloseDrainsRequeuedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var accepted []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var batch struct {
+			Events []Event `json:"events"`
+		}
+		json.Unmarshal(body, &batch)
+
+		var result batchResponse
+		mu.Lock()
+		for i, event := range batch.Events {
+			if event.Message == "bad" {
+				result.Rejected = append(result.Rejected, i)
+			} else {
+				result.Accepted = append(result.Accepted, i)
+				accepted = append(accepted, event.Message)
+			}
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusMultiStatus)
+		json.NewEncoder(w).Encode(result)
+	}))
+	defer ts.Close()
+
+	var dropped []DropReason
+	c, err := NewClient(Config{
+		Endpoint:           ts.URL,
+		APIKey:             "test-key",
+		ForceBatchEndpoint: true,
+		OnDrop: func(event Event, reason DropReason) {
+			dropped = append(dropped, reason)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.CaptureMessage("good", LevelInfo)
+	c.CaptureMessage("bad", LevelInfo)
+
+	err = c.Close()
+	if err == nil || !strings.Contains(err.Error(), "1 event(s) undelivered") {
+		t.Errorf("Close() = %v, want 1 event undelivered", err)
+	}
+	if len(dropped) != 1 || dropped[0] != DropReasonRejected {
+		t.Errorf("dropped = %v, want [%s]", dropped, DropReasonRejected)
+	}
+	if len(accepted) != 1 || accepted[0] != "good" {
+		t.Errorf("accepted = %v, want [good]", accepted)
+	}
+	if n := c.queue.len(); n != 0 {
+		t.Errorf("%d event(s) left in the queue", n)
+	}
+}
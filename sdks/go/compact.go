@@ -0,0 +1,95 @@
+package pulsekit
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// compactStack shrinks a stack trace for Config.CompactStacktrace: file
+// paths are reduced to their base name, standard library frames are removed
+// and runs of identical frames (recursion) are collapsed into one frame
+// with a Count. A stack made only of standard library frames is kept.
+func compactStack(frames []StackFrame) []StackFrame {
+	out := make([]StackFrame, 0, len(frames))
+	for _, frame := range frames {
+		if isStdlibFrame(frame) {
+			continue
+		}
+		frame.File = path.Base(frame.File)
+		if n := len(out); n > 0 && sameFrame(out[n-1], frame) {
+			if out[n-1].Count == 0 {
+				out[n-1].Count = 1
+			}
+			out[n-1].Count++
+			continue
+		}
+		out = append(out, frame)
+	}
+	if len(out) == 0 {
+		return frames
+	}
+	return out
+}
+
+func sameFrame(a, b StackFrame) bool {
+	return a.Function == b.Function && a.File == b.File && a.Line == b.Line
+}
+
+// isStdlibFrame reports whether frame belongs to the standard library. A
+// frame with an absolute file path does if the file lies under GOROOT.
+// Binaries built with -trimpath record relative paths, so there the frame's
+// package must instead be outside the main module and its dependencies and,
+// like every standard library import path, have no dot in its first
+// element.
+func isStdlibFrame(frame StackFrame) bool {
+	if frame.Function == "" {
+		return false
+	}
+	if goroot := filepath.ToSlash(runtime.GOROOT()); goroot != "" && filepath.IsAbs(frame.File) {
+		return strings.HasPrefix(frame.File, goroot+"/src/")
+	}
+	pkg := functionPackage(frame.Function)
+	if pkg == "main" || inBuildModules(pkg) {
+		return false
+	}
+	first, _, _ := strings.Cut(pkg, "/")
+	return !strings.Contains(first, ".")
+}
+
+// functionPackage returns the import path of a fully qualified function
+// name such as "example.com/app/db.(*Conn).Query".
+func functionPackage(fn string) string {
+	slash := strings.LastIndex(fn, "/")
+	if i := strings.Index(fn[slash+1:], "."); i >= 0 {
+		return fn[:slash+1+i]
+	}
+	return fn
+}
+
+// buildModules lists the paths of the main module and its dependencies.
+var buildModules = sync.OnceValue(func() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	paths := []string{info.Main.Path}
+	for _, dep := range info.Deps {
+		paths = append(paths, dep.Path)
+	}
+	return paths
+})
+
+// inBuildModules reports whether package pkg belongs to one of the
+// buildModules.
+func inBuildModules(pkg string) bool {
+	for _, mod := range buildModules() {
+		if mod != "" && (pkg == mod || strings.HasPrefix(pkg, mod+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package pulsekit
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestIsStdlibFrame(t *testing.T) {
+	goroot := runtime.GOROOT()
+	tests := []struct {
+		name  string
+		frame StackFrame
+		want  bool
+	}{
+		{"stdlib", StackFrame{Function: "net/http.HandlerFunc.ServeHTTP", File: goroot + "/src/net/http/server.go"}, true},
+		{"runtime", StackFrame{Function: "runtime.goexit", File: goroot + "/src/runtime/asm_amd64.s"}, true},
+		{"dotless module", StackFrame{Function: "myapp/internal/db.(*Conn).Query", File: "/home/dev/myapp/internal/db/conn.go"}, false},
+		{"dotless root module", StackFrame{Function: "myapp.run", File: "/home/dev/myapp/run.go"}, false},
+		{"main", StackFrame{Function: "main.main", File: "/home/dev/myapp/main.go"}, false},
+		{"dependency", StackFrame{Function: "github.com/lib/pq.(*conn).query", File: "/go/pkg/mod/github.com/lib/pq@v1.10.9/conn.go"}, false},
+		{"trimpath stdlib", StackFrame{Function: "net/http.(*conn).serve", File: "net/http/server.go"}, true},
+		{"trimpath main module", StackFrame{Function: "github.com/pulsekit/go.compactStack", File: "github.com/pulsekit/go/compact.go"}, false},
+		{"no function", StackFrame{File: goroot + "/src/runtime/proc.go"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStdlibFrame(tt.frame); got != tt.want {
+				t.Errorf("isStdlibFrame(%+v) = %v, want %v", tt.frame, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompactStackKeepsDotlessModuleFrames(t *testing.T) {
+	goroot := runtime.GOROOT()
+	frames := []StackFrame{
+		{Function: "myapp/internal/db.query", File: "/src/myapp/internal/db/db.go", Line: 10},
+		{Function: "myapp/internal/db.query", File: "/src/myapp/internal/db/db.go", Line: 10},
+		{Function: "net/http.HandlerFunc.ServeHTTP", File: goroot + "/src/net/http/server.go", Line: 2136},
+		{Function: "main.main", File: "/src/myapp/main.go", Line: 5},
+	}
+	got := compactStack(frames)
+	if len(got) != 2 {
+		t.Fatalf("compactStack kept %d frames, want 2: %+v", len(got), got)
+	}
+	if got[0].File != "db.go" || got[0].Count != 2 {
+		t.Errorf("first frame = %+v, want db.go with Count 2", got[0])
+	}
+	if got[1].Function != "main.main" {
+		t.Errorf("second frame = %q, want main.main", got[1].Function)
+	}
+}
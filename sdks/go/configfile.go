@@ -0,0 +1,181 @@
+package pulsekit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// fileConfig is the on-disk form of Config. Only settings that can be
+// expressed as data are supported; callbacks and interfaces must still be
+// set in code.
+type fileConfig struct {
+	Endpoint                    string                      `json:"endpoint"`
+	APIKey                      string                      `json:"api_key"`
+	Environment                 string                      `json:"environment"`
+	Release                     string                      `json:"release"`
+	BatchSize                   int                         `json:"batch_size"`
+	FlushInterval               duration                    `json:"flush_interval"`
+	Debug                       bool                        `json:"debug"`
+	SyncAbove                   Level                       `json:"sync_above"`
+	SyncTimeout                 duration                    `json:"sync_timeout"`
+	AttachProcessInfo           bool                        `json:"attach_process_info"`
+	ForceBatchEndpoint          bool                        `json:"force_batch_endpoint"`
+	ForceSingleEndpoint         bool                        `json:"force_single_endpoint"`
+	FingerprintFromRootCause    bool                        `json:"fingerprint_from_root_cause"`
+	SynchronousMode             bool                        `json:"synchronous_mode"`
+	AutoDetectRelease           bool                        `json:"auto_detect_release"`
+	MaxTags                     int                         `json:"max_tags"`
+	MaxMetadataKeys             int                         `json:"max_metadata_keys"`
+	AttachGoroutineID           bool                        `json:"attach_goroutine_id"`
+	UserAgent                   string                      `json:"user_agent"`
+	Projects                    map[string]string           `json:"projects"`
+	ForceHTTP2                  bool                        `json:"force_http2"`
+	ReportStartup               bool                        `json:"report_startup"`
+	SmoothDelivery              bool                        `json:"smooth_delivery"`
+	FallbackToStderr            bool                        `json:"fallback_to_stderr"`
+	MaxRetries                  int                         `json:"max_retries"`
+	EventsPerSecond             float64                     `json:"events_per_second"`
+	ReservedErrorBudgetFraction float64                     `json:"reserved_error_budget_fraction"`
+	DryRun                      bool                        `json:"dry_run"`
+	ContentType                 string                      `json:"content_type"`
+	FlushBytes                  int                         `json:"flush_bytes"`
+	DedupeWindow                duration                    `json:"dedupe_window"`
+	MaxDedupeEntries            int                         `json:"max_dedupe_entries"`
+	MaxQueueSize                int                         `json:"max_queue_size"`
+	QueueType                   QueueType                   `json:"queue_type"`
+	TagsByLevel                 map[Level]map[string]string `json:"tags_by_level"`
+}
+
+// duration decodes a time.Duration from a string such as "5s".
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %s", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// ParseConfig decodes a JSON configuration document into a Config.
+// Environment variables referenced as $VAR or ${VAR} in string values are
+// expanded after the document is parsed, so secrets such as the API key can
+// stay out of the file and their values cannot change its structure. $$ is
+// a literal $. Durations are written as strings like "5s". Unknown keys are
+// an error.
+func ParseConfig(data []byte) (Config, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+	expanded, err := json.Marshal(expandEnvValues(doc))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+
+	var fc fileConfig
+	dec = json.NewDecoder(bytes.NewReader(expanded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return Config{
+		Endpoint:                    fc.Endpoint,
+		APIKey:                      fc.APIKey,
+		Environment:                 fc.Environment,
+		Release:                     fc.Release,
+		BatchSize:                   fc.BatchSize,
+		FlushInterval:               time.Duration(fc.FlushInterval),
+		Debug:                       fc.Debug,
+		SyncAbove:                   fc.SyncAbove,
+		SyncTimeout:                 time.Duration(fc.SyncTimeout),
+		AttachProcessInfo:           fc.AttachProcessInfo,
+		ForceBatchEndpoint:          fc.ForceBatchEndpoint,
+		ForceSingleEndpoint:         fc.ForceSingleEndpoint,
+		FingerprintFromRootCause:    fc.FingerprintFromRootCause,
+		SynchronousMode:             fc.SynchronousMode,
+		AutoDetectRelease:           fc.AutoDetectRelease,
+		MaxTags:                     fc.MaxTags,
+		MaxMetadataKeys:             fc.MaxMetadataKeys,
+		AttachGoroutineID:           fc.AttachGoroutineID,
+		UserAgent:                   fc.UserAgent,
+		Projects:                    fc.Projects,
+		ForceHTTP2:                  fc.ForceHTTP2,
+		ReportStartup:               fc.ReportStartup,
+		SmoothDelivery:              fc.SmoothDelivery,
+		FallbackToStderr:            fc.FallbackToStderr,
+		MaxRetries:                  fc.MaxRetries,
+		EventsPerSecond:             fc.EventsPerSecond,
+		ReservedErrorBudgetFraction: fc.ReservedErrorBudgetFraction,
+		DryRun:                      fc.DryRun,
+		ContentType:                 fc.ContentType,
+		FlushBytes:                  fc.FlushBytes,
+		DedupeWindow:                time.Duration(fc.DedupeWindow),
+		MaxDedupeEntries:            fc.MaxDedupeEntries,
+		MaxQueueSize:                fc.MaxQueueSize,
+		QueueType:                   fc.QueueType,
+		TagsByLevel:                 fc.TagsByLevel,
+	}, nil
+}
+
+// expandEnvValues expands environment references in the string values of a
+// decoded JSON document. Object keys are left alone.
+func expandEnvValues(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return expandEnv(v)
+	case map[string]interface{}:
+		for k, elem := range v {
+			v[k] = expandEnvValues(elem)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = expandEnvValues(elem)
+		}
+	}
+	return v
+}
+
+// expandEnv replaces $VAR and ${VAR} in s with the values of environment
+// variables. Unlike os.ExpandEnv, $$ yields a literal $, and a $ that does
+// not start a variable name (such as $1 or $-) is kept as is.
+func expandEnv(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+	return os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		if c := name[0]; c != '_' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') {
+			return "$" + name
+		}
+		return os.Getenv(name)
+	})
+}
+
+// InitFromFile reads a JSON configuration file, decodes it with ParseConfig
+// and initializes the default client with the result.
+func InitFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return err
+	}
+	return Init(config)
+}
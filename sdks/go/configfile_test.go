@@ -0,0 +1,96 @@
+package pulsekit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`{
+		"endpoint": "https://pulsekit.example.com",
+		"api_key": "key",
+		"flush_interval": "5s",
+		"batch_size": 50,
+		"events_per_second": 2.5,
+		"projects": {"billing": "billing-key"},
+		"tags_by_level": {"error": {"team": "core"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Endpoint != "https://pulsekit.example.com" || config.APIKey != "key" {
+		t.Errorf("Endpoint, APIKey = %q, %q", config.Endpoint, config.APIKey)
+	}
+	if config.FlushInterval != 5*time.Second {
+		t.Errorf("FlushInterval = %v, want 5s", config.FlushInterval)
+	}
+	if config.BatchSize != 50 || config.EventsPerSecond != 2.5 {
+		t.Errorf("BatchSize, EventsPerSecond = %d, %v", config.BatchSize, config.EventsPerSecond)
+	}
+	if config.Projects["billing"] != "billing-key" {
+		t.Errorf("Projects = %v", config.Projects)
+	}
+	if config.TagsByLevel[LevelError]["team"] != "core" {
+		t.Errorf("TagsByLevel = %v", config.TagsByLevel)
+	}
+}
+
+func TestParseConfigExpandsEnv(t *testing.T) {
+	t.Setenv("PK_KEY", `ab"c`)
+	t.Setenv("PK_INJECT", `x", "debug": true, "release": "y`)
+	t.Setenv("PK_FLUSH", "2s")
+	t.Setenv("PK_PROJECT_KEY", "billing-key")
+
+	config, err := ParseConfig([]byte(`{
+		"api_key": "${PK_KEY}",
+		"environment": "$PK_INJECT",
+		"flush_interval": "$PK_FLUSH",
+		"release": "v$1-$$PK_KEY-cost$-5",
+		"user_agent": "$PK_UNSET",
+		"projects": {"billing": "${PK_PROJECT_KEY}"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.APIKey != `ab"c` {
+		t.Errorf("APIKey = %q, want %q", config.APIKey, `ab"c`)
+	}
+	if config.Environment != `x", "debug": true, "release": "y` || config.Debug {
+		t.Errorf("Environment = %q, Debug = %v: the value changed the document", config.Environment, config.Debug)
+	}
+	if config.FlushInterval != 2*time.Second {
+		t.Errorf("FlushInterval = %v, want 2s", config.FlushInterval)
+	}
+	if config.Release != "v$1-$PK_KEY-cost$-5" {
+		t.Errorf("Release = %q, want literal $ kept", config.Release)
+	}
+	if config.UserAgent != "" {
+		t.Errorf("UserAgent = %q, want unset variable to expand to empty", config.UserAgent)
+	}
+	if config.Projects["billing"] != "billing-key" {
+		t.Errorf("Projects = %v", config.Projects)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "syntax", data: `{"api_key": }`, want: "invalid character"},
+		{name: "unknown key", data: `{"api_keys": "key"}`, want: "unknown field"},
+		{name: "numeric duration", data: `{"flush_interval": 5}`, want: "duration must be a string"},
+		{name: "bad duration", data: `{"flush_interval": "soon"}`, want: "invalid duration"},
+		{name: "wrong type", data: `{"batch_size": "50"}`, want: "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseConfig(%s) error = %v, want it to contain %q", tt.data, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package pulsekit
+
+import "context"
+
+// contextKey namespaces values the SDK stores in a context.Context.
+type contextKey int
+
+const (
+	tagsContextKey contextKey = iota
+	userContextKey
+	responseContextKey
+	tenantContextKey
+)
+
+// ContextWithTags returns a copy of ctx carrying tags, merged over any tags
+// already stored in ctx. CaptureNow attaches them to events.
+func ContextWithTags(ctx context.Context, tags map[string]string) context.Context {
+	return context.WithValue(ctx, tagsContextKey, mergeTags(TagsFromContext(ctx), tags))
+}
+
+// TagsFromContext returns the tags stored in ctx by ContextWithTags.
+func TagsFromContext(ctx context.Context) map[string]string {
+	tags, _ := ctx.Value(tagsContextKey).(map[string]string)
+	return tags
+}
+
+// CaptureNow captures an event using the default client, snapshotting
+// context values first.
+func CaptureNow(ctx context.Context, event Event) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CaptureNow(ctx, event)
+}
+
+// CaptureNow copies context-derived data into the event on the calling
+// goroutine before queueing it, so nothing is lost if ctx is canceled before
+// the event is sent. Tags from ContextWithTags are merged under the event's
+// own tags, the user is read as with WithUserFromContext, then
+// Config.ContextExtractor runs.
+func (c *Client) CaptureNow(ctx context.Context, event Event) {
+	c.applyContext(ctx, &event)
+	c.Capture(event)
+}
+
+// applyContext copies values from ctx into event. Event tags take
+// precedence over context tags.
+func (c *Client) applyContext(ctx context.Context, event *Event) {
+	if ctxTags := TagsFromContext(ctx); len(ctxTags) > 0 {
+		event.Tags = mergeTags(ctxTags, event.Tags)
+	}
+	if event.User == nil {
+		event.User = c.userFromContext(ctx)
+	}
+	if event.TenantID == "" {
+		WithTenant(c.tenantFromContext(ctx))(event)
+	}
+	applyResponse(ctx, event)
+	if c.config.ContextExtractor != nil {
+		c.config.ContextExtractor(ctx, event)
+	}
+}
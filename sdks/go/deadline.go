@@ -0,0 +1,56 @@
+package pulsekit
+
+import (
+	"fmt"
+	"time"
+)
+
+// WithDeadline drops the event instead of sending it if it is still
+// undelivered at t, for signals that are worthless once stale. Expired
+// events are reported to OnDrop with DropReasonTTLExpired and counted in
+// Stats.DeadlineExpired.
+func WithDeadline(t time.Time) EventOption {
+	return func(e *Event) {
+		e.deadline = t
+	}
+}
+
+// dropExpired removes events whose deadline has passed or that have been
+// queued for longer than Config.MaxDeliveryAge.
+func (c *Client) dropExpired(events []Event) []Event {
+	now := c.config.Clock.Now()
+	kept := events[:0]
+	for _, event := range events {
+		if !event.deadline.IsZero() && now.After(event.deadline) {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Dropping %q event past its deadline\n", event.Type)
+			}
+			c.stats.add(func(s *Stats) { s.DeadlineExpired++ })
+			c.drop(event, DropReasonTTLExpired)
+			continue
+		}
+		if c.config.MaxDeliveryAge > 0 && !event.queuedAt.IsZero() && now.Sub(event.queuedAt) > c.config.MaxDeliveryAge {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Dropping %q event queued for over %v\n", event.Type, c.config.MaxDeliveryAge)
+			}
+			c.stats.add(func(s *Stats) { s.Expired++ })
+			c.drop(event, DropReasonTTLExpired)
+			continue
+		}
+		kept = append(kept, event)
+	}
+	return kept
+}
+
+// annotateQueueLatency records how long each queued event waited under the
+// "_queue_latency_ms" metadata key. Events sent synchronously are skipped.
+func (c *Client) annotateQueueLatency(events []Event) {
+	now := c.config.Clock.Now()
+	for i := range events {
+		if events[i].queuedAt.IsZero() {
+			continue
+		}
+		events[i].Metadata = copyMap(events[i].Metadata)
+		events[i].Metadata["_queue_latency_ms"] = durationMs(now.Sub(events[i].queuedAt))
+	}
+}
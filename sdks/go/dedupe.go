@@ -0,0 +1,168 @@
+package pulsekit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const defaultMaxDedupeEntries = 10000
+
+// DedupeStore records which fingerprints were sent recently, backing
+// Config.DedupeWindow. The default store is in memory and per process; a
+// shared store (e.g. the redisdedupe subpackage) suppresses duplicates
+// across a fleet of instances.
+type DedupeStore interface {
+	// Seen reports whether fingerprint was recorded within the last window.
+	// If it was not, it must record it, atomically with the check, so that
+	// concurrent callers see exactly one false.
+	Seen(fingerprint string, window time.Duration) bool
+}
+
+// DedupeForgetter is implemented by a DedupeStore that can remove a recorded
+// fingerprint. The client uses it to undo the record of an event rejected
+// after the dedupe check (e.g. by TryCapture on a full queue), so repeats of
+// an event that was never sent are not suppressed.
+type DedupeForgetter interface {
+	Forget(fingerprint string)
+}
+
+// memoryDedupeStore adapts dedupeCache to DedupeStore using the client's
+// clock.
+type memoryDedupeStore struct {
+	cache *dedupeCache
+	clock Clock
+}
+
+func (m *memoryDedupeStore) Seen(fingerprint string, _ time.Duration) bool {
+	return m.cache.seen(fingerprint, m.clock.Now())
+}
+
+func (m *memoryDedupeStore) Forget(fingerprint string) {
+	m.cache.forget(fingerprint)
+}
+
+// dedupeCache remembers when each fingerprint was last sent. Entries are
+// kept in a list ordered by send time (most recent first), which doubles as
+// the LRU order: expired entries are swept from the back, and the oldest
+// entry is evicted when the cache is full.
+type dedupeCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+type dedupeEntry struct {
+	fingerprint string
+	sentAt      time.Time
+}
+
+func newDedupeCache(window time.Duration, maxEntries int) *dedupeCache {
+	return &dedupeCache{
+		window:     window,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// seen reports whether fingerprint was sent within the window before now.
+// If not, it is recorded as sent at now.
+func (d *dedupeCache) seen(fingerprint string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if el, ok := d.entries[fingerprint]; ok {
+		entry := el.Value.(*dedupeEntry)
+		if now.Sub(entry.sentAt) < d.window {
+			return true
+		}
+		entry.sentAt = now
+		d.order.MoveToFront(el)
+		return false
+	}
+
+	d.entries[fingerprint] = d.order.PushFront(&dedupeEntry{fingerprint: fingerprint, sentAt: now})
+	for d.order.Len() > d.maxEntries {
+		d.removeLocked(d.order.Back())
+	}
+	return false
+}
+
+// sweep evicts entries older than the window. Only expired entries are
+// visited, so a sweep with nothing to do costs a single comparison.
+func (d *dedupeCache) sweep(now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for el := d.order.Back(); el != nil; el = d.order.Back() {
+		if now.Sub(el.Value.(*dedupeEntry).sentAt) < d.window {
+			return
+		}
+		d.removeLocked(el)
+	}
+}
+
+// forget removes fingerprint.
+func (d *dedupeCache) forget(fingerprint string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if el, ok := d.entries[fingerprint]; ok {
+		d.removeLocked(el)
+	}
+}
+
+// clear forgets every fingerprint.
+func (d *dedupeCache) clear() {
+	d.mu.Lock()
+	d.order.Init()
+	d.entries = make(map[string]*list.Element)
+	d.mu.Unlock()
+}
+
+func (d *dedupeCache) removeLocked(el *list.Element) {
+	d.order.Remove(el)
+	delete(d.entries, el.Value.(*dedupeEntry).fingerprint)
+}
+
+// memoryDedupe returns the in-memory dedupe cache, or nil if a custom
+// DedupeStore is configured.
+func (c *Client) memoryDedupe() *dedupeCache {
+	if m, ok := c.dedupe.(*memoryDedupeStore); ok {
+		return m.cache
+	}
+	return nil
+}
+
+// dedupeKey returns the fingerprint used to detect duplicate events.
+func dedupeKey(event *Event) string {
+	if event.Fingerprint != "" {
+		return event.Fingerprint
+	}
+	return hashFingerprint(event.Type, string(event.Level), event.Message)
+}
+
+// duplicate reports whether the event repeats one sent within DedupeWindow,
+// dropping it if so. Otherwise the event is recorded as sent.
+func (c *Client) duplicate(event *Event) bool {
+	if c.dedupe == nil {
+		return false
+	}
+	key := dedupeKey(event)
+	if c.dedupe.Seen(key, c.config.DedupeWindow) {
+		c.drop(*event, DropReasonDuplicate)
+		return true
+	}
+	event.dedupeKey = key
+	return false
+}
+
+// forgetDuplicate undoes the dedupe record of an event that was rejected
+// after passing the dedupe check, if the store supports it.
+func (c *Client) forgetDuplicate(event Event) {
+	if forgetter, ok := c.dedupe.(DedupeForgetter); ok && event.dedupeKey != "" {
+		forgetter.Forget(event.dedupeKey)
+	}
+}
@@ -0,0 +1,51 @@
+package pulsekit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupeIgnoresRateLimitedEvents(t *testing.T) {
+	ts := newTestServer(t)
+	clock := NewFakeClock(time.Unix(0, 0))
+	c := newTestClient(t, ts, Config{
+		Clock:           clock,
+		DedupeWindow:    time.Minute,
+		EventsPerSecond: 1,
+	})
+
+	c.CaptureMessage("a", LevelInfo)
+	c.CaptureMessage("b", LevelInfo) // rate limited
+	clock.Advance(2 * time.Second)
+	c.CaptureMessage("b", LevelInfo) // not a duplicate of the dropped event
+	c.Flush()
+
+	if got := len(ts.received()); got != 2 {
+		t.Fatalf("received %d events, want 2", got)
+	}
+}
+
+func TestDedupeForgetsEventsRejectedByTryCapture(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts, Config{
+		DedupeWindow: time.Minute,
+		MaxQueueSize: 1,
+		BatchSize:    10,
+	})
+
+	if !c.TryCapture(Event{Type: "log", Message: "a"}) {
+		t.Fatal("first TryCapture rejected")
+	}
+	if c.TryCapture(Event{Type: "log", Message: "b"}) {
+		t.Fatal("TryCapture on a full queue accepted")
+	}
+	c.Flush()
+	if !c.TryCapture(Event{Type: "log", Message: "b"}) {
+		t.Fatal("retried event rejected as a duplicate")
+	}
+	c.Flush()
+
+	if got := len(ts.received()); got != 2 {
+		t.Fatalf("received %d events, want 2", got)
+	}
+}
@@ -0,0 +1,199 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// truncatedMarker replaces metadata nested deeper than MaxMetadataDepth.
+	truncatedMarker = "[truncated]"
+	// cycleMarker replaces a metadata value that refers back to one of its
+	// ancestors.
+	cycleMarker = "[cycle]"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// applyDepthLimit rebuilds the event's metadata with containers nested
+// deeper than Config.MaxMetadataDepth replaced by "[truncated]" and cycles
+// replaced by "[cycle]". Maps, slices and structs are copied into plain maps
+// and slices as they would be encoded; values implementing json.Marshaler
+// are kept as is. The caller's metadata is never modified.
+func (c *Client) applyDepthLimit(event *Event) {
+	if c.config.MaxMetadataDepth <= 0 || len(event.Metadata) == 0 {
+		return
+	}
+	w := depthWalker{max: c.config.MaxMetadataDepth, seen: make(map[uintptr]bool)}
+	metadata := make(map[string]interface{}, len(event.Metadata))
+	for k, v := range event.Metadata {
+		metadata[k] = w.walk(reflect.ValueOf(v), 1)
+	}
+	event.Metadata = metadata
+
+	if w.truncated > 0 && c.config.Debug {
+		fmt.Printf("[PulseKit] Truncated %d nested metadata value(s) in %q event\n", w.truncated, event.Type)
+	}
+}
+
+// depthWalker copies nested values while tracking depth and the containers
+// on the current path.
+type depthWalker struct {
+	max       int
+	seen      map[uintptr]bool
+	truncated int
+}
+
+// walk copies v, which sits at the given nesting level (top-level metadata
+// values are at level 1).
+func (w *depthWalker) walk(v reflect.Value, level int) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type().Implements(jsonMarshalerType) && v.CanInterface() {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return w.walk(v.Elem(), level)
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return w.visit(v.Pointer(), func() interface{} { return w.walk(v.Elem(), level) })
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if w.tooDeep(level) {
+			return truncatedMarker
+		}
+		return w.visit(v.Pointer(), func() interface{} {
+			out := make(map[string]interface{}, v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				out[fmt.Sprint(iter.Key().Interface())] = w.walk(iter.Value(), level+1)
+			}
+			return out
+		})
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		if w.tooDeep(level) {
+			return truncatedMarker
+		}
+		return w.visit(v.Pointer(), func() interface{} { return w.walkElems(v, level) })
+	case reflect.Array:
+		if w.tooDeep(level) {
+			return truncatedMarker
+		}
+		return w.walkElems(v, level)
+	case reflect.Struct:
+		if w.tooDeep(level) {
+			return truncatedMarker
+		}
+		out := make(map[string]interface{})
+		w.walkFields(v, level, out)
+		return out
+	}
+	if v.CanInterface() {
+		return v.Interface()
+	}
+	return readOnlyValue(v)
+}
+
+// readOnlyValue returns the basic value held by v, which was reached through
+// an unexported field and so cannot be returned with Interface.
+func readOnlyValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+	return nil
+}
+
+func (w *depthWalker) tooDeep(level int) bool {
+	if level > w.max {
+		w.truncated++
+		return true
+	}
+	return false
+}
+
+// visit runs fn with ptr marked as being on the current path, returning the
+// cycle marker instead if it already is.
+func (w *depthWalker) visit(ptr uintptr, fn func() interface{}) interface{} {
+	if w.seen[ptr] {
+		w.truncated++
+		return cycleMarker
+	}
+	w.seen[ptr] = true
+	defer delete(w.seen, ptr)
+	return fn()
+}
+
+func (w *depthWalker) walkElems(v reflect.Value, level int) []interface{} {
+	out := make([]interface{}, v.Len())
+	for i := range out {
+		out[i] = w.walk(v.Index(i), level+1)
+	}
+	return out
+}
+
+// walkFields copies the exported fields of struct v into out under their
+// JSON names, flattening embedded structs and pointers to structs as
+// encoding/json does.
+func (w *depthWalker) walkFields(v reflect.Value, level int, out map[string]interface{}) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		fv := v.Field(i)
+		if f.Anonymous && name == "" {
+			switch {
+			case f.Type.Kind() == reflect.Struct:
+				w.walkFields(fv, level, out)
+				continue
+			case f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct:
+				if !fv.IsNil() {
+					w.visit(fv.Pointer(), func() interface{} {
+						w.walkFields(fv.Elem(), level, out)
+						return nil
+					})
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		out[name] = w.walk(fv, level+1)
+	}
+}
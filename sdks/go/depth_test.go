@@ -0,0 +1,63 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type depthBase struct {
+	ID   int
+	Name string `json:"name"`
+}
+
+type DepthExtra struct {
+	Note string `json:"note,omitempty"`
+}
+
+type depthHidden struct {
+	Secret string
+}
+
+type depthRecord struct {
+	depthBase
+	*DepthExtra
+	*depthHidden
+	Count int `json:"count"`
+}
+
+func TestDepthWalkerEmbeddedFieldsMatchJSON(t *testing.T) {
+	records := []depthRecord{
+		{depthBase: depthBase{ID: 7, Name: "a"}, DepthExtra: &DepthExtra{Note: "n"}, depthHidden: &depthHidden{Secret: "s"}, Count: 2},
+		{depthBase: depthBase{ID: 8, Name: "b"}, Count: 3},
+	}
+	for _, record := range records {
+		w := depthWalker{max: 10, seen: make(map[uintptr]bool)}
+		got, err := json.Marshal(w.walk(reflect.ValueOf(record), 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := json.Marshal(record)
+		if !jsonEqual(got, want) {
+			t.Errorf("walk encoded %s, encoding/json %s", got, want)
+		}
+	}
+}
+
+func TestReadOnlyValue(t *testing.T) {
+	v := reflect.ValueOf(struct{ n int }{n: 5}).Field(0)
+	if v.CanInterface() {
+		t.Fatal("unexported field is interfaceable")
+	}
+	if got := readOnlyValue(v); got != int64(5) {
+		t.Errorf("readOnlyValue = %v, want 5", got)
+	}
+}
+
+func jsonEqual(a, b []byte) bool {
+	var x, y interface{}
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
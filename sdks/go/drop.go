@@ -0,0 +1,50 @@
+package pulsekit
+
+import "fmt"
+
+// DropReason explains why an event was discarded instead of delivered.
+type DropReason string
+
+const (
+	// DropReasonSampled is used when SampleRate (or the trace's sampled
+	// flag under TraceSampling) discarded the event.
+	DropReasonSampled DropReason = "sampling"
+	// DropReasonRateLimited is used when EventsPerSecond or
+	// PerKeyEventsPerSecond discarded the event.
+	DropReasonRateLimited DropReason = "rate_limited"
+	// DropReasonQueueFull is used when the event was evicted from a full
+	// queue (MaxQueueSize) without an OverflowSink, or rejected by
+	// TryCapture.
+	DropReasonQueueFull DropReason = "queue_full"
+	// DropReasonTTLExpired is used when the event was still undelivered past
+	// its WithDeadline deadline or MaxDeliveryAge.
+	DropReasonTTLExpired DropReason = "ttl_expired"
+	// DropReasonDuplicate is used when the event repeated one sent within
+	// DedupeWindow.
+	DropReasonDuplicate DropReason = "duplicate"
+	// DropReasonRejected is used when the server rejected the event: either
+	// repeatedly within partially failed batches, or outright with 400 or
+	// 413 when sent on its own (including during batch isolation).
+	DropReasonRejected DropReason = "rejected"
+	// DropReasonInvalid is used when the event cannot be serialized.
+	DropReasonInvalid DropReason = "invalid"
+	// DropReasonUnroutable is used when the event's project has no API key.
+	DropReasonUnroutable DropReason = "unroutable"
+	// DropReasonSendFailed is used when the event could not be delivered
+	// after all retries and FallbackToStderr is not set.
+	DropReasonSendFailed DropReason = "send_failed"
+)
+
+// drop reports a discarded event to Config.OnDrop. Panics in the callback are
+// recovered so they cannot disrupt delivery.
+func (c *Client) drop(event Event, reason DropReason) {
+	if c.config.OnDrop == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil && c.config.Debug {
+			fmt.Printf("[PulseKit] OnDrop callback panicked: %v\n", r)
+		}
+	}()
+	c.config.OnDrop(event, reason)
+}
@@ -0,0 +1,30 @@
+package pulsekit
+
+import "fmt"
+
+// enrichAtSend applies Config.EnrichAtSend to each event.
+func (c *Client) enrichAtSend(events []Event) {
+	if c.config.EnrichAtSend == nil {
+		return
+	}
+	for i := range events {
+		c.enrich(&events[i])
+	}
+}
+
+// enrich runs Config.EnrichAtSend on a copy of event, keeping the result
+// unless the callback panics.
+func (c *Client) enrich(event *Event) {
+	defer func() {
+		if r := recover(); r != nil && c.config.Debug {
+			fmt.Printf("[PulseKit] EnrichAtSend callback panicked: %v\n", r)
+		}
+	}()
+	enriched := *event
+	enriched.Metadata = copyMap(enriched.Metadata)
+	enriched.Tags = copyMap(enriched.Tags)
+	enriched.Contexts = copyMap(enriched.Contexts)
+	c.config.EnrichAtSend(&enriched)
+	c.filterSecrets(&enriched)
+	*event = enriched
+}
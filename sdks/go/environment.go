@@ -0,0 +1,29 @@
+package pulsekit
+
+import "strings"
+
+// environmentAliases maps common environment spellings to a canonical name.
+var environmentAliases = map[string]string{
+	"prod":  "production",
+	"prd":   "production",
+	"live":  "production",
+	"stage": "staging",
+	"stg":   "staging",
+	"dev":   "development",
+	"devel": "development",
+	"local": "development",
+	"test":  "testing",
+	"tst":   "testing",
+	"qa":    "testing",
+}
+
+// CanonicalEnvironment is a Config.NormalizeEnvironment function that trims
+// and lowercases env and maps common aliases (prod, prd, stg, dev, qa, ...)
+// to production, staging, development or testing.
+func CanonicalEnvironment(env string) string {
+	env = strings.ToLower(strings.TrimSpace(env))
+	if canonical, ok := environmentAliases[env]; ok {
+		return canonical
+	}
+	return env
+}
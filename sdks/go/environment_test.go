@@ -0,0 +1,32 @@
+package pulsekit
+
+import "testing"
+
+func TestNormalizeEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		preserve bool
+		eventEnv string
+		want     string
+	}{
+		{name: "configured", eventEnv: "", want: "production"},
+		{name: "overridden", eventEnv: "Staging", want: "production"},
+		{name: "preserved blank", preserve: true, eventEnv: "", want: "production"},
+		{name: "preserved", preserve: true, eventEnv: "PRD-eu", want: "PRD-eu"},
+	}
+	ts := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, ts, Config{
+				Environment:          "PROD",
+				NormalizeEnvironment: CanonicalEnvironment,
+				PreserveEventFields:  tt.preserve,
+			})
+			event := Event{Environment: tt.eventEnv}
+			c.prepare(&event)
+			if event.Environment != tt.want {
+				t.Errorf("Environment = %q, want %q", event.Environment, tt.want)
+			}
+		})
+	}
+}
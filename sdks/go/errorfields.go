@@ -0,0 +1,108 @@
+package pulsekit
+
+// FieldsError is implemented by errors that carry structured fields. The
+// fields of every error in the chain are merged into the event's metadata.
+type FieldsError interface {
+	error
+	Fields() map[string]interface{}
+}
+
+// TagsError is implemented by errors that carry tags. The tags of every
+// error in the chain are merged into the event's tags.
+type TagsError interface {
+	error
+	Tags() map[string]string
+}
+
+// LabelsError is implemented by errors that carry metric-style labels, such
+// as {"code": "E_TIMEOUT"}. Labels are merged into the event's tags like
+// TagsError tags.
+type LabelsError interface {
+	error
+	Labels() map[string]string
+}
+
+// WithTagsFromError merges the tags and labels exposed by err and the errors
+// it wraps (see TagsError and LabelsError) into the event's tags. Tags
+// already on the event take precedence. CaptureException does this
+// automatically for the captured error.
+func WithTagsFromError(err error) EventOption {
+	return func(e *Event) {
+		if tags := errorTags(errorChain(err)); tags != nil {
+			e.Tags = mergeTags(tags, e.Tags)
+		}
+	}
+}
+
+// errorTags collects the tags and labels of chain, with outer errors taking
+// precedence over the errors they wrap. It returns nil if there are none.
+func errorTags(chain []error) map[string]string {
+	var tags map[string]string
+	add := func(m map[string]string) {
+		for k, v := range m {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[k] = v
+		}
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if le, ok := chain[i].(LabelsError); ok {
+			add(le.Labels())
+		}
+		if te, ok := chain[i].(TagsError); ok {
+			add(te.Tags())
+		}
+	}
+	return tags
+}
+
+// applyErrorFields merges fields, tags and labels exposed by err and the errors it
+// wraps into event. Outer errors take precedence over the errors they wrap,
+// and values already on the event take precedence over both.
+func applyErrorFields(err error, event *Event) {
+	chain := errorChain(err)
+	var metadata map[string]interface{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if fe, ok := chain[i].(FieldsError); ok {
+			for k, v := range fe.Fields() {
+				if metadata == nil {
+					metadata = make(map[string]interface{})
+				}
+				metadata[k] = v
+			}
+		}
+	}
+	if metadata != nil {
+		for k, v := range event.Metadata {
+			metadata[k] = v
+		}
+		event.Metadata = metadata
+	}
+	if tags := errorTags(chain); tags != nil {
+		event.Tags = mergeTags(tags, event.Tags)
+	}
+}
+
+// errorChain returns err followed by every error it wraps, depth first,
+// following both Unwrap() error and Unwrap() []error.
+func errorChain(err error) []error {
+	var chain []error
+	var walk func(error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err)
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				walk(e)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
@@ -0,0 +1,103 @@
+package pulsekit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// valueError adapts a non-error failure value so it can be captured as an
+// exception.
+type valueError struct {
+	value   interface{}
+	message string
+}
+
+func (e *valueError) Error() string { return e.message }
+
+// exceptionType returns the Go type name reported for err. Values captured
+// through CaptureErrorValue report the type of the original value.
+func exceptionType(err error) string {
+	if ve, ok := err.(*valueError); ok {
+		return fmt.Sprintf("%T", ve.value)
+	}
+	return fmt.Sprintf("%T", err)
+}
+
+// CaptureErrorValue captures an arbitrary failure value using the default
+// client.
+func CaptureErrorValue(v interface{}, opts ...EventOption) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CaptureErrorValue(v, opts...)
+}
+
+// CaptureErrorValue captures a failure value that does not implement error,
+// such as a struct carrying a code, message and details. The exception type is
+// the value's Go type, the message comes from its String method or a
+// Message/Msg field, and the value itself is attached as "error_details"
+// metadata. Values that do implement error are captured as with
+// CaptureException, and nil is handled according to Config.OnNilError.
+func (c *Client) CaptureErrorValue(v interface{}, opts ...EventOption) {
+	if v == nil {
+		c.nilError(opts)
+		return
+	}
+	if err, ok := v.(error); ok {
+		c.captureException(err, capturePCs(3), opts)
+		return
+	}
+
+	err := &valueError{value: v, message: valueMessage(v)}
+	opts = append([]EventOption{
+		WithMetadata(map[string]interface{}{"error_details": valueDetails(v)}),
+	}, opts...)
+	c.captureException(err, capturePCs(3), opts)
+}
+
+// valueMessage derives a message for a failure value.
+func valueMessage(v interface{}) string {
+	if s, ok := v.(fmt.Stringer); ok {
+		return s.String()
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		for _, name := range []string{"Message", "Msg"} {
+			if f := rv.FieldByName(name); f.IsValid() && f.Kind() == reflect.String && f.CanInterface() {
+				return f.String()
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String {
+			if m := rv.MapIndex(reflect.ValueOf("message").Convert(rv.Type().Key())); m.IsValid() {
+				return fmt.Sprint(m.Interface())
+			}
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// valueDetails converts a failure value into a JSON-friendly form, falling
+// back to its %+v representation when it cannot be marshaled. Numbers are
+// kept as json.Number so large integers survive the round trip.
+func valueDetails(v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	var details interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&details); err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return details
+}
@@ -0,0 +1,21 @@
+package pulsekit
+
+import "testing"
+
+// TestCaptureErrorValueNil checks that a nil value follows OnNilError like
+// CaptureException(nil).
+func TestCaptureErrorValueNil(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts, Config{SynchronousMode: true, OnNilError: NilErrorCapture})
+
+	c.CaptureErrorValue(nil, WithTags(map[string]string{"job": "sync"}))
+
+	events := ts.received()
+	if len(events) != 1 {
+		t.Fatalf("received %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Type != "pulsekit.sdk.nil_error" || event.Tags["job"] != "sync" {
+		t.Errorf("event type %q with tags %v, want a nil_error warning with the caller's tags", event.Type, event.Tags)
+	}
+}
@@ -0,0 +1,29 @@
+package pulsekit
+
+// reset clears the client's accumulated state (queued events and check-ins,
+// stats, tag cardinality tracking, the dedupe cache, rate-limiter buckets
+// and retry-budget tokens) so tests and benchmarks can start clean between
+// runs without constructing a new client. It lives in a _test file so it is
+// compiled only into tests.
+func (c *Client) reset() {
+	c.mu.Lock()
+	c.popLocked(-1)
+	c.checkIns = nil
+	c.mu.Unlock()
+
+	c.stats.add(func(s *Stats) { *s = Stats{} })
+	c.tagValues.clear()
+
+	if cache := c.memoryDedupe(); cache != nil {
+		cache.clear()
+	}
+	if c.limiter != nil {
+		c.limiter.refill(c.config.Clock.Now())
+	}
+	if c.keyLimiter != nil {
+		c.keyLimiter.clear()
+	}
+	if c.retryBudget != nil {
+		c.retryBudget.refill(c.config.Clock.Now())
+	}
+}
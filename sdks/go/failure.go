@@ -0,0 +1,84 @@
+package pulsekit
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// Failure categories recorded in Stats.Failures.
+const (
+	FailureDNS               = "dns"
+	FailureTLS               = "tls"
+	FailureTimeout           = "timeout"
+	FailureConnectionRefused = "connection_refused"
+	FailureNetwork           = "network"
+	FailureUnauthorized      = "http_401"
+	FailureForbidden         = "http_403"
+	FailureRateLimited       = "http_429"
+	FailureClientError       = "http_4xx"
+	FailureServerError       = "http_5xx"
+	FailureInvalidResponse   = "invalid_response"
+	FailureEncode            = "encode"
+)
+
+// classifyFailure buckets a send error by likely remediation, so a bad API
+// key can be told apart from a network partition.
+func classifyFailure(err error) string {
+	var se *statusError
+	if errors.As(err, &se) {
+		switch {
+		case se.code == 401:
+			return FailureUnauthorized
+		case se.code == 403:
+			return FailureForbidden
+		case se.code == 429:
+			return FailureRateLimited
+		case se.code >= 500:
+			return FailureServerError
+		case se.code >= 400:
+			return FailureClientError
+		}
+		return FailureInvalidResponse
+	}
+	var ee *encodeError
+	if errors.As(err, &ee) {
+		return FailureEncode
+	}
+
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var unknownAuthErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertErr x509.CertificateInvalidError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &dnsErr):
+		return FailureDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &unknownAuthErr),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidCertErr):
+		return FailureTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return FailureTimeout
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return FailureConnectionRefused
+	case errors.As(err, &netErr):
+		return FailureNetwork
+	}
+	return FailureInvalidResponse
+}
+
+// recordFailure counts a failed send in Stats.Failures.
+func (c *Client) recordFailure(err error) {
+	category := classifyFailure(err)
+	c.stats.add(func(s *Stats) {
+		if s.Failures == nil {
+			s.Failures = make(map[string]int64)
+		}
+		s.Failures[category]++
+	})
+}
@@ -0,0 +1,38 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// fallbackWriter serializes events as JSON lines. Writes are serialized so
+// lines from concurrent flushes never interleave.
+type fallbackWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Write writes each event as a single JSON line. Events that cannot be
+// marshaled are skipped; the last error is returned.
+func (f *fallbackWriter) Write(events []Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var lastErr error
+	enc := json.NewEncoder(f.w)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// writeFallback writes undeliverable events to the fallback writer.
+func (c *Client) writeFallback(events []Event) {
+	if err := c.fallback.Write(events); err != nil && c.config.Debug {
+		fmt.Printf("[PulseKit] Failed to write events to fallback: %v\n", err)
+	}
+}
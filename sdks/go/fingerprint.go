@@ -0,0 +1,61 @@
+package pulsekit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// hashFingerprint derives a stable fingerprint from its parts.
+func hashFingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// rootCause follows the errors.Unwrap chain to the innermost error.
+func rootCause(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// rootCauseFingerprint groups errors by the type and message of their root
+// cause, so wrapping with dynamic context does not split issues.
+func rootCauseFingerprint(err error) string {
+	root := rootCause(err)
+	return hashFingerprint(fmt.Sprintf("%T", root), root.Error())
+}
+
+// messageNormalizers replace variable tokens in messages with placeholders,
+// most specific first.
+var messageNormalizers = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "<email>"},
+	{regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), "<uuid>"},
+	{regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`), "<hex>"},
+	{regexp.MustCompile(`\b\d+(?:\.\d+)?`), "<num>"},
+}
+
+// normalizeMessage replaces e-mail addresses, UUIDs, hex strings and numbers
+// in msg with placeholders, so "user 42 not found" and "user 7 not found"
+// normalize to the same text.
+func normalizeMessage(msg string) string {
+	for _, n := range messageNormalizers {
+		msg = n.re.ReplaceAllString(msg, n.placeholder)
+	}
+	return msg
+}
+
+// messageFingerprint groups events by type and normalized message.
+func messageFingerprint(event *Event) string {
+	return hashFingerprint(event.Type, normalizeMessage(event.Message))
+}
@@ -0,0 +1,89 @@
+package pulsekit
+
+import (
+	"context"
+	"sync"
+)
+
+// FlushGroup collects related events, such as an error and the events
+// giving its context, so they are delivered together and in capture order.
+// Events join a group through WithFlushGroup or FlushGroup.Capture and stay
+// in it, bypassing the queue, BatchSize and SyncAbove, until Flush sends
+// them in a single request (one per project). Events in a group that is
+// never flushed are not sent. A FlushGroup is safe for concurrent use.
+type FlushGroup struct {
+	client *Client
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewFlushGroup returns a flush group for the default client, or nil if no
+// client is registered. A nil group is valid: WithFlushGroup(nil) has no
+// effect and its Flush does nothing.
+func NewFlushGroup() *FlushGroup {
+	client := getDefaultClient()
+	if client == nil {
+		return nil
+	}
+	return client.NewFlushGroup()
+}
+
+// NewFlushGroup returns an empty flush group sending through c.
+func (c *Client) NewFlushGroup() *FlushGroup {
+	return &FlushGroup{client: c}
+}
+
+// WithFlushGroup holds the event in g until g.Flush is called instead of
+// queueing it. Events carrying a WithMetadataStream value are always sent
+// on their own.
+func WithFlushGroup(g *FlushGroup) EventOption {
+	return func(e *Event) {
+		e.group = g
+	}
+}
+
+// Capture captures event into the group.
+func (g *FlushGroup) Capture(event Event) {
+	if g == nil {
+		return
+	}
+	event.group = g
+	g.client.Capture(event)
+}
+
+// add appends a prepared and finalized event to the group.
+func (g *FlushGroup) add(event Event) {
+	event.group = nil
+	g.mu.Lock()
+	g.events = append(g.events, event)
+	g.mu.Unlock()
+}
+
+// Len returns the number of events waiting in the group.
+func (g *FlushGroup) Len() int {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.events)
+}
+
+// Flush sends the group's events in one batch, in the order they were
+// captured, and empties the group so it can be reused. It blocks until the
+// send completes and returns an error describing any events that could not
+// be delivered.
+func (g *FlushGroup) Flush() error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	events := g.events
+	g.events = nil
+	g.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+	return g.client.sendEvents(context.Background(), events).error()
+}
@@ -0,0 +1,21 @@
+package pulsekit
+
+import (
+	"bytes"
+	"runtime"
+)
+
+// goroutineID returns the current goroutine's ID as printed in the first
+// line of runtime.Stack ("goroutine 42 [running]:"). The runtime does not
+// expose this ID officially, so it is only meant for correlating events while
+// debugging. It returns "" if the header cannot be parsed.
+func goroutineID() string {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	i := bytes.IndexByte(b, ' ')
+	if i <= 0 {
+		return ""
+	}
+	return string(b[:i])
+}
@@ -0,0 +1,39 @@
+package pulsekit
+
+// SetGroupKeyOverride forces the fingerprint of error events captured by the
+// default client to key.
+func SetGroupKeyOverride(key string) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.SetGroupKeyOverride(key)
+}
+
+// ClearGroupKeyOverride removes the default client's group key override.
+func ClearGroupKeyOverride() {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.ClearGroupKeyOverride()
+}
+
+// SetGroupKeyOverride forces the fingerprint of every error and fatal event
+// captured from now on to key, collapsing an incident's error storm into a
+// single issue. An empty key clears the override. It is safe to call
+// concurrently with captures.
+func (c *Client) SetGroupKeyOverride(key string) {
+	c.groupKey.Store(key)
+}
+
+// ClearGroupKeyOverride removes the group key override.
+func (c *Client) ClearGroupKeyOverride() {
+	c.groupKey.Store("")
+}
+
+// groupKeyOverride returns the current override, or "" if none is set.
+func (c *Client) groupKeyOverride() string {
+	key, _ := c.groupKey.Load().(string)
+	return key
+}
@@ -0,0 +1,42 @@
+package pulsekit
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroupKeyOverrideWithDedupeWindow(t *testing.T) {
+	ts := newTestServer(t)
+	var dropped []DropReason
+	c := newTestClient(t, ts, Config{
+		DedupeWindow: time.Minute,
+		OnDrop:       func(_ Event, reason DropReason) { dropped = append(dropped, reason) },
+	})
+
+	c.SetGroupKeyOverride("incident-42")
+	c.CaptureException(errors.New("connection refused"))
+	c.CaptureException(errors.New("timeout"))
+	c.CaptureException(errors.New("no route to host"))
+	c.Flush()
+
+	if len(dropped) != 0 {
+		t.Fatalf("dropped %v, want none", dropped)
+	}
+	events := ts.received()
+	if len(events) != 3 {
+		t.Fatalf("received %d events, want 3", len(events))
+	}
+	for _, event := range events {
+		if event.Fingerprint != "incident-42" {
+			t.Errorf("fingerprint = %q, want %q", event.Fingerprint, "incident-42")
+		}
+	}
+
+	// A genuine repeat is still suppressed.
+	c.CaptureException(errors.New("timeout"))
+	c.Flush()
+	if len(dropped) != 1 || dropped[0] != DropReasonDuplicate {
+		t.Fatalf("dropped %v, want [%s]", dropped, DropReasonDuplicate)
+	}
+}
@@ -0,0 +1,135 @@
+// Package pulsekitgrpc provides gRPC interceptors that capture failed calls
+// with PulseKit. It is a separate module so the core SDK does not depend on
+// gRPC.
+package pulsekitgrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pulsekit "github.com/pulsekit/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Option configures the interceptors.
+type Option func(*options)
+
+type options struct {
+	client          *pulsekit.Client
+	captureCanceled bool
+}
+
+// WithClient captures with client instead of the default client.
+func WithClient(client *pulsekit.Client) Option {
+	return func(o *options) {
+		o.client = client
+	}
+}
+
+// WithCaptureCanceled also captures calls that failed with codes.Canceled,
+// which are skipped by default since they usually mean the caller gave up.
+func WithCaptureCanceled() Option {
+	return func(o *options) {
+		o.captureCanceled = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// capture reports err for method. Errors without a gRPC status are reported
+// as codes.Unknown, matching what the peer observes.
+func (o *options) capture(kind, method string, err error) {
+	if err == nil {
+		return
+	}
+	code := status.Code(err)
+	if code == codes.OK || (code == codes.Canceled && !o.captureCanceled) {
+		return
+	}
+
+	eventOpts := []pulsekit.EventOption{
+		pulsekit.WithOrigin(pulsekit.OriginGRPC),
+		pulsekit.WithTags(map[string]string{
+			"grpc.kind":   kind,
+			"grpc.method": method,
+			"grpc.code":   code.String(),
+		}),
+		pulsekit.WithFingerprint("grpc:" + method + ":" + code.String()),
+	}
+	if o.client != nil {
+		o.client.CaptureException(err, eventOpts...)
+	} else {
+		pulsekit.CaptureException(err, eventOpts...)
+	}
+}
+
+// UnaryServerInterceptor captures errors returned by unary handlers.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		o.capture("server", info.FullMethod, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor captures errors returned by streaming handlers.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		o.capture("server", info.FullMethod, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor captures errors returned by unary calls.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		o.capture("client", method, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor captures errors from opening a stream and from
+// receiving on it. io.EOF, which marks the normal end of a stream, is not
+// captured.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			o.capture("client", method, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, opts: o, method: method}, nil
+	}
+}
+
+// clientStream captures the first error received on a client stream.
+type clientStream struct {
+	grpc.ClientStream
+	opts     *options
+	method   string
+	reported bool
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil && !errors.Is(err, io.EOF) && !s.reported {
+		s.reported = true
+		s.opts.capture("client", s.method, err)
+	}
+	return err
+}
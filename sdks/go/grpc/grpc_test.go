@@ -0,0 +1,98 @@
+package pulsekitgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	pulsekit "github.com/pulsekit/go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestClient returns a client that sends synchronously to a test server
+// and a function returning the events received so far.
+func newTestClient(t *testing.T) (*pulsekit.Client, func() []pulsekit.Event) {
+	var mu sync.Mutex
+	var events []pulsekit.Event
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event pulsekit.Event
+		json.Unmarshal(body, &event)
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+	}))
+	t.Cleanup(ts.Close)
+
+	client, err := pulsekit.NewClient(pulsekit.Config{
+		Endpoint:        ts.URL,
+		APIKey:          "test-key",
+		SynchronousMode: true,
+	})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client, func() []pulsekit.Event {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]pulsekit.Event(nil), events...)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		opts     []Option
+		wantCode string
+	}{
+		{name: "success", err: nil},
+		{name: "OK status", err: status.Error(codes.OK, "")},
+		{name: "canceled", err: status.Error(codes.Canceled, "caller gave up")},
+		{name: "canceled captured", err: status.Error(codes.Canceled, "caller gave up"), opts: []Option{WithCaptureCanceled()}, wantCode: "Canceled"},
+		{name: "internal", err: status.Error(codes.Internal, "boom"), wantCode: "Internal"},
+		{name: "plain error", err: errors.New("boom"), wantCode: "Unknown"},
+	}
+
+	const method = "/pulsekit.test.Service/Method"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, received := newTestClient(t)
+			interceptor := UnaryServerInterceptor(append(tt.opts, WithClient(client))...)
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "response", tt.err
+			}
+
+			resp, err := interceptor(context.Background(), "request", &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			if resp != "response" || err != tt.err {
+				t.Errorf("interceptor returned (%v, %v), want (response, %v)", resp, err, tt.err)
+			}
+
+			events := received()
+			if tt.wantCode == "" {
+				if len(events) != 0 {
+					t.Fatalf("captured %d event(s), want none", len(events))
+				}
+				return
+			}
+			if len(events) != 1 {
+				t.Fatalf("captured %d event(s), want 1", len(events))
+			}
+			tags := events[0].Tags
+			if tags["grpc.code"] != tt.wantCode {
+				t.Errorf("grpc.code = %q, want %q", tags["grpc.code"], tt.wantCode)
+			}
+			if tags["grpc.method"] != method || tags["grpc.kind"] != "server" {
+				t.Errorf("tags = %v, want method %q and kind server", tags, method)
+			}
+		})
+	}
+}
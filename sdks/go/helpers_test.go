@@ -0,0 +1,65 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testServer records the events posted to it.
+type testServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	events []Event
+}
+
+// newTestServer starts a server accepting events on the single-event and
+// batch endpoints. It is closed when the test ends.
+func newTestServer(t testing.TB) *testServer {
+	ts := &testServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var events []Event
+		if strings.HasSuffix(r.URL.Path, "/batch") {
+			var batch struct {
+				Events []Event `json:"events"`
+			}
+			json.Unmarshal(body, &batch)
+			events = batch.Events
+		} else if strings.HasSuffix(r.URL.Path, "/events") {
+			var event Event
+			json.Unmarshal(body, &event)
+			events = []Event{event}
+		}
+		ts.mu.Lock()
+		ts.events = append(ts.events, events...)
+		ts.mu.Unlock()
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// received returns the events posted so far.
+func (ts *testServer) received() []Event {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]Event(nil), ts.events...)
+}
+
+// newTestClient returns a client sending to ts, closed when the test ends.
+func newTestClient(t testing.TB, ts *testServer, config Config) *Client {
+	config.Endpoint = ts.URL
+	if config.APIKey == "" {
+		config.APIKey = "test-key"
+	}
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
@@ -0,0 +1,43 @@
+package pulsekit
+
+import "fmt"
+
+// WithLazyMetadata adds a metadata value computed by fn. fn is only called
+// once the event has passed filtering and is about to be queued, so
+// expensive values are never computed for discarded events.
+func WithLazyMetadata(key string, fn func() interface{}) EventOption {
+	return func(e *Event) {
+		if e.lazyMetadata == nil {
+			e.lazyMetadata = make(map[string]func() interface{})
+		}
+		e.lazyMetadata[key] = fn
+	}
+}
+
+// resolveLazyMetadata evaluates the event's lazy metadata producers. A
+// producer that panics is skipped.
+func (c *Client) resolveLazyMetadata(event *Event) {
+	if len(event.lazyMetadata) == 0 {
+		return
+	}
+	metadata := copyMap(event.Metadata)
+	for key, fn := range event.lazyMetadata {
+		if v, ok := c.callLazy(key, fn); ok {
+			metadata[key] = v
+		}
+	}
+	event.Metadata = metadata
+	event.lazyMetadata = nil
+}
+
+func (c *Client) callLazy(key string, fn func() interface{}) (v interface{}, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Lazy metadata %q panicked: %v\n", key, r)
+			}
+			ok = false
+		}
+	}()
+	return fn(), true
+}
@@ -0,0 +1,72 @@
+package pulsekit
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	defaultMaxTags         = 50
+	defaultMaxMetadataKeys = 100
+)
+
+// applyKeyLimits drops tags and metadata keys beyond the configured limits.
+// The first keys in sorted order are kept so truncation is deterministic, and
+// the number of dropped keys is recorded under "_tags_truncated" and
+// "_metadata_truncated" metadata markers. Truncated maps are copied so the
+// caller's maps are never modified.
+func (c *Client) applyKeyLimits(event *Event) {
+	tagsDropped := len(event.Tags) - c.config.MaxTags
+	if tagsDropped > 0 {
+		event.Tags = truncateKeys(event.Tags, c.config.MaxTags)
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Dropped %d tag(s) from %q event over MaxTags\n", tagsDropped, event.Type)
+		}
+	}
+
+	metadataDropped := len(event.Metadata) - c.config.MaxMetadataKeys
+	if metadataDropped > 0 {
+		event.Metadata = truncateKeys(event.Metadata, c.config.MaxMetadataKeys)
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Dropped %d metadata key(s) from %q event over MaxMetadataKeys\n", metadataDropped, event.Type)
+		}
+	}
+
+	if tagsDropped > 0 || metadataDropped > 0 {
+		event.Metadata = copyMap(event.Metadata)
+		if tagsDropped > 0 {
+			event.Metadata["_tags_truncated"] = tagsDropped
+		}
+		if metadataDropped > 0 {
+			event.Metadata["_metadata_truncated"] = metadataDropped
+		}
+	}
+}
+
+// truncateKeys returns a copy of m holding only its first n keys in sorted
+// order.
+func truncateKeys[V any](m map[string]V, n int) map[string]V {
+	out := make(map[string]V, n)
+	for _, k := range sortedKeys(m)[:n] {
+		out[k] = m[k]
+	}
+	return out
+}
+
+// copyMap returns a shallow copy of m, never nil.
+func copyMap[V any](m map[string]V) map[string]V {
+	out := make(map[string]V, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package pulsekit
+
+import "runtime"
+
+// MemoryInfo is a snapshot of Go runtime memory statistics attached as the
+// "memory" context by CaptureExceptionWithMemStats.
+type MemoryInfo struct {
+	HeapAlloc    uint64 `json:"heap_alloc"`
+	HeapInuse    uint64 `json:"heap_inuse"`
+	HeapObjects  uint64 `json:"heap_objects"`
+	Sys          uint64 `json:"sys"`
+	NumGC        uint32 `json:"num_gc"`
+	PauseTotalNs uint64 `json:"pause_total_ns"`
+	NumGoroutine int    `json:"num_goroutine"`
+}
+
+// CaptureExceptionWithMemStats captures an error with memory statistics
+// using the default client.
+func CaptureExceptionWithMemStats(err error, opts ...EventOption) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CaptureExceptionWithMemStats(err, opts...)
+}
+
+// CaptureExceptionWithMemStats captures an error with stack trace and a
+// snapshot of runtime.MemStats in the "memory" context, to help diagnose
+// allocation-related failures. runtime.ReadMemStats stops the world while
+// it runs, so call this only for errors likely to be memory related, not on
+// every error in a hot path.
+func (c *Client) CaptureExceptionWithMemStats(err error, opts ...EventOption) {
+	if err == nil {
+		c.nilError(opts)
+		return
+	}
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	memory := MemoryInfo{
+		HeapAlloc:    m.HeapAlloc,
+		HeapInuse:    m.HeapInuse,
+		HeapObjects:  m.HeapObjects,
+		Sys:          m.Sys,
+		NumGC:        m.NumGC,
+		PauseTotalNs: m.PauseTotalNs,
+		NumGoroutine: runtime.NumGoroutine(),
+	}
+	opts = append([]EventOption{func(e *Event) { e.setContext("memory", memory) }}, opts...)
+	c.captureException(err, capturePCs(3), opts)
+}
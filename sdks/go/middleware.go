@@ -0,0 +1,100 @@
+package pulsekit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// MechanismHTTPMiddleware is the mechanism type for panics recovered by
+// Middleware.
+const MechanismHTTPMiddleware = "http.middleware"
+
+// Middleware recovers panics from next, captures them with the default client
+// and responds with 500 Internal Server Error.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := getDefaultClient()
+		if client == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		client.Middleware(next).ServeHTTP(w, r)
+	})
+}
+
+// Middleware recovers panics from next, captures them and responds with 500
+// Internal Server Error unless a response was already started. Events are
+// grouped by the request's route pattern (see Config.RoutePatternFunc)
+// rather than its concrete path, so /users/123 and /users/456 produce a
+// single issue. The status and size of the response are attached to error
+// and fatal events captured with the request (WithRequest) or its context
+// (CaptureNow).
+func (c *Client) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseRecorder{ResponseWriter: w}
+		r = r.WithContext(context.WithValue(r.Context(), responseContextKey, rw))
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			if rw.status == 0 {
+				http.Error(rw, "Internal Server Error", http.StatusInternalServerError)
+			}
+			c.capturePanic(rec, r)
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (c *Client) capturePanic(rec interface{}, r *http.Request) {
+	err, ok := rec.(error)
+	if !ok {
+		err = fmt.Errorf("%v", rec)
+	}
+
+	route := c.routePattern(r)
+	c.CapturePanic(rec,
+		WithLevel(LevelError),
+		WithMechanism(MechanismHTTPMiddleware, false),
+		WithOrigin(OriginHTTP),
+		WithTags(c.limitTagCardinality(map[string]string{
+			"http.method": r.Method,
+			"http.route":  route,
+		})),
+		WithMetadata(map[string]interface{}{
+			"path":   r.URL.Path,
+			"method": r.Method,
+			"route":  route,
+		}),
+		WithFingerprint(routeFingerprint(r.Method, route, err)),
+		WithTraceFromRequest(r),
+		WithRequest(r),
+	)
+}
+
+// routePattern returns the route pattern for r, preferring
+// Config.RoutePatternFunc, then the pattern matched by http.ServeMux, and
+// finally the concrete path.
+func (c *Client) routePattern(r *http.Request) string {
+	if c.config.RoutePatternFunc != nil {
+		if pattern := c.config.RoutePatternFunc(r); pattern != "" {
+			return pattern
+		}
+	}
+	if pattern := serveMuxPattern(r); pattern != "" {
+		return pattern
+	}
+	return r.URL.Path
+}
+
+// routeFingerprint groups errors by route and root error rather than by
+// concrete path.
+func routeFingerprint(method, route string, err error) string {
+	root := rootCause(err)
+	return hashFingerprint("http", method, route, fmt.Sprintf("%T", root), root.Error())
+}
@@ -0,0 +1,50 @@
+package pulsekit
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// NilErrorPolicy selects what CaptureException and its variants do when
+// given a nil error.
+type NilErrorPolicy string
+
+const (
+	// NilErrorIgnore silently does nothing. It is the default.
+	NilErrorIgnore NilErrorPolicy = "ignore"
+	// NilErrorLog prints the caller's location when Debug is enabled.
+	NilErrorLog NilErrorPolicy = "log"
+	// NilErrorCapture sends a "pulsekit.sdk.nil_error" warning event with
+	// the caller's stack trace, surfacing error handling paths that capture
+	// nil by mistake.
+	NilErrorCapture NilErrorPolicy = "capture"
+)
+
+// nilError applies Config.OnNilError for a nil error passed to the exported
+// Capture method that called it, with that method's opts.
+func (c *Client) nilError(opts []EventOption) {
+	switch c.config.OnNilError {
+	case NilErrorLog:
+		if !c.config.Debug {
+			return
+		}
+		location := "unknown location"
+		if pcs := trimSDKFrames(capturePCs(3)); len(pcs) > 0 {
+			frame, _ := runtime.CallersFrames(pcs).Next()
+			location = fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		fmt.Printf("[PulseKit] Nil error captured at %s\n", location)
+	case NilErrorCapture:
+		event := Event{
+			Type:    "pulsekit.sdk.nil_error",
+			Level:   LevelWarning,
+			Message: "nil error captured",
+			Origin:  OriginSDK,
+			pcs:     trimSDKFrames(capturePCs(3)),
+		}
+		for _, opt := range opts {
+			opt(&event)
+		}
+		c.enqueue(event)
+	}
+}
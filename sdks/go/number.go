@@ -0,0 +1,18 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// Int64 wraps an integer metadata value so it is always encoded as an exact
+// JSON integer, even after being copied through interface{} values or
+// decoded and re-encoded. Use it for order, customer and other large IDs.
+func Int64(v int64) json.Number {
+	return json.Number(strconv.FormatInt(v, 10))
+}
+
+// Uint64 is like Int64 for unsigned values.
+func Uint64(v uint64) json.Number {
+	return json.Number(strconv.FormatUint(v, 10))
+}
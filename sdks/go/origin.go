@@ -0,0 +1,25 @@
+package pulsekit
+
+// Origins identify how an event was produced, so automatic instrumentation
+// can be told apart from intentional captures.
+const (
+	// OriginManual is used for events captured directly through the API.
+	OriginManual = "manual"
+	// OriginHTTP is used for panics recovered by Middleware.
+	OriginHTTP = "auto.http"
+	// OriginGRPC is used for errors captured by the gRPC interceptors.
+	OriginGRPC = "auto.grpc"
+	// OriginPanic is used for panics captured by Recover and CapturePanic.
+	OriginPanic = "auto.panic"
+	// OriginSlog is used for events forwarded by SlogHandler.
+	OriginSlog = "auto.slog"
+	// OriginSDK is used for events the SDK reports about itself.
+	OriginSDK = "auto.sdk"
+)
+
+// WithOrigin overrides the origin recorded for the event.
+func WithOrigin(origin string) EventOption {
+	return func(e *Event) {
+		e.Origin = origin
+	}
+}
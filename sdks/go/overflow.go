@@ -0,0 +1,53 @@
+package pulsekit
+
+import (
+	"fmt"
+	"io"
+)
+
+// OverflowSink receives events evicted from a full queue (see
+// Config.MaxQueueSize) instead of them being dropped.
+type OverflowSink interface {
+	Write(events []Event) error
+}
+
+// OverflowSinkFunc adapts a function to an OverflowSink.
+type OverflowSinkFunc func(events []Event) error
+
+// Write calls f(events).
+func (f OverflowSinkFunc) Write(events []Event) error {
+	return f(events)
+}
+
+// WriterOverflowSink returns a sink writing overflow events to w as JSON
+// lines, e.g. to a spill file.
+func WriterOverflowSink(w io.Writer) OverflowSink {
+	return &fallbackWriter{w: w}
+}
+
+// ClientOverflowSink returns a sink forwarding overflow events, unmodified,
+// to another client, e.g. one configured with a secondary endpoint.
+func ClientOverflowSink(client *Client) OverflowSink {
+	return OverflowSinkFunc(func(events []Event) error {
+		for _, event := range events {
+			client.CaptureRaw(event)
+		}
+		return nil
+	})
+}
+
+// spill hands evicted events to Config.OverflowSink. It reports whether the
+// sink accepted them.
+func (c *Client) spill(events []Event) bool {
+	if c.config.OverflowSink == nil {
+		return false
+	}
+	if err := c.config.OverflowSink.Write(events); err != nil {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Overflow sink failed: %v\n", err)
+		}
+		return false
+	}
+	c.stats.add(func(s *Stats) { s.Overflowed += int64(len(events)) })
+	return true
+}
@@ -0,0 +1,71 @@
+package pulsekit
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MechanismPanic is the mechanism type for panics captured by Recover and
+// CapturePanic.
+const MechanismPanic = "panic"
+
+// Recover captures a panic in progress using the default client. It must be
+// deferred directly:
+//
+//	defer pulsekit.Recover()
+//
+// The panic is stopped; the event is sent before Recover returns.
+func Recover() {
+	if v := recover(); v != nil {
+		client := getDefaultClient()
+		if client == nil {
+			return
+		}
+		client.CapturePanic(v)
+		client.Flush()
+	}
+}
+
+// Recover captures a panic in progress. It must be deferred directly:
+//
+//	defer client.Recover()
+//
+// The panic is stopped; the event is sent before Recover returns.
+func (c *Client) Recover() {
+	if v := recover(); v != nil {
+		c.CapturePanic(v)
+		c.Flush()
+	}
+}
+
+// CapturePanic captures a recovered panic value using the default client.
+func CapturePanic(v interface{}, opts ...EventOption) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CapturePanic(v, opts...)
+}
+
+// CapturePanic captures a value returned by recover() as a fatal, unhandled
+// exception. The exception type is the dynamic type of the value (e.g.,
+// "runtime.boundsError", "string", "*main.MyError"), which distinguishes
+// runtime faults from custom panics.
+func (c *Client) CapturePanic(v interface{}, opts ...EventOption) {
+	if v == nil {
+		return
+	}
+	err, ok := v.(error)
+	if !ok {
+		err = fmt.Errorf("%v", v)
+	}
+	valueType := reflect.TypeOf(v).String()
+
+	opts = append([]EventOption{
+		WithLevel(LevelFatal),
+		WithMechanism(MechanismPanic, false),
+		WithOrigin(OriginPanic),
+		func(e *Event) { e.Exception.Type = valueType },
+	}, opts...)
+	c.captureException(err, capturePCs(3), opts)
+}
@@ -0,0 +1,94 @@
+package pulsekit
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// processStart approximates the process start time as the moment the package
+// was initialized.
+var processStart = time.Now().UTC()
+
+// sensitiveNames are substrings of flag and variable names whose values are
+// redacted from the reported command line and captured locals.
+var sensitiveNames = []string{"password", "passwd", "secret", "token", "key", "auth", "credential"}
+
+const redacted = "[REDACTED]"
+
+// ProcessInfo describes the running process.
+type ProcessInfo struct {
+	PID        int      `json:"pid"`
+	Executable string   `json:"executable,omitempty"`
+	Args       []string `json:"args,omitempty"`
+	StartTime  string   `json:"start_time"`
+}
+
+// collectProcessInfo gathers process information once so it can be reused on
+// every event.
+func collectProcessInfo() *ProcessInfo {
+	exe, err := os.Executable()
+	if err != nil {
+		exe = ""
+	}
+	return &ProcessInfo{
+		PID:        os.Getpid(),
+		Executable: exe,
+		Args:       redactArgs(os.Args),
+		StartTime:  processStart.Format(time.RFC3339),
+	}
+}
+
+// redactArgs replaces the values of flags that look like secrets. Both the
+// "--flag=value" and "--flag value" forms are handled; in the latter, an
+// argument starting with "-" is taken to be the next flag, not a value.
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	redactNext := false
+	for i, arg := range args {
+		// A flag following a value-less sensitive flag is not its value.
+		wasSensitive := redactNext
+		redactNext = false
+		if wasSensitive && !strings.HasPrefix(arg, "-") {
+			out[i] = redacted
+			continue
+		}
+		out[i] = arg
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !isSensitiveName(name) {
+			continue
+		}
+		if hasValue {
+			out[i] = arg[:strings.Index(arg, "=")+1] + redacted
+		} else {
+			redactNext = true
+		}
+	}
+	return out
+}
+
+// redactVars returns a copy of vars with the values of secret-looking names
+// redacted.
+func redactVars(vars map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(vars))
+	for name, v := range vars {
+		if isSensitiveName(name) {
+			v = redacted
+		}
+		out[name] = v
+	}
+	return out
+}
+
+func isSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
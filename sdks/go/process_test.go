@@ -0,0 +1,24 @@
+package pulsekit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"app", "--password=hunter2"}, []string{"app", "--password=" + redacted}},
+		{[]string{"app", "--token", "abc", "run"}, []string{"app", "--token", redacted, "run"}},
+		{[]string{"app", "--use-auth", "--port", "8080"}, []string{"app", "--use-auth", "--port", "8080"}},
+		{[]string{"app", "--use-auth", "--api-key", "abc"}, []string{"app", "--use-auth", "--api-key", redacted}},
+		{[]string{"app", "--verbose", "file"}, []string{"app", "--verbose", "file"}},
+	}
+	for _, tt := range tests {
+		if got := redactArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("redactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
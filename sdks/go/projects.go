@@ -0,0 +1,84 @@
+package pulsekit
+
+import "fmt"
+
+// projectBatch holds the events destined for a single project.
+type projectBatch struct {
+	apiKey string
+	events []Event
+}
+
+// projectFor returns the project an event is routed to: Event.Project if set,
+// otherwise the result of Config.ProjectRouter. "" means the default project.
+func (c *Client) projectFor(event *Event) string {
+	if event.Project != "" {
+		return event.Project
+	}
+	if c.config.ProjectRouter != nil {
+		return c.config.ProjectRouter(*event)
+	}
+	return ""
+}
+
+// apiKeyFor resolves a project name to its API key. Unknown projects fall
+// back to the default APIKey when one is configured.
+func (c *Client) apiKeyFor(project string) (string, bool) {
+	if project != "" {
+		if key, ok := c.config.Projects[project]; ok {
+			return key, true
+		}
+	}
+	c.keyMu.RLock()
+	defer c.keyMu.RUnlock()
+	return c.config.APIKey, c.config.APIKey != ""
+}
+
+// SetAPIKey replaces the default client's API key.
+func SetAPIKey(key string) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.SetAPIKey(key)
+}
+
+// SetAPIKey replaces the default API key at runtime, e.g. during a key
+// rotation. Requests already in flight finish with the old key; every send
+// started afterwards uses the new one. Per-project keys are not affected.
+func (c *Client) SetAPIKey(key string) {
+	c.keyMu.Lock()
+	c.config.APIKey = key
+	c.keyMu.Unlock()
+}
+
+// groupByProject splits events into per-project batches, preserving the
+// order of events within each batch. Events for unknown projects with no
+// default API key are dropped.
+func (c *Client) groupByProject(events []Event) []projectBatch {
+	if len(c.config.Projects) == 0 && c.config.ProjectRouter == nil {
+		key, _ := c.apiKeyFor("")
+		return []projectBatch{{apiKey: key, events: events}}
+	}
+
+	var batches []projectBatch
+	index := make(map[string]int)
+	for i := range events {
+		project := c.projectFor(&events[i])
+		key, ok := c.apiKeyFor(project)
+		if !ok {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Dropping %q event for unknown project %q\n", events[i].Type, project)
+			}
+			c.drop(events[i], DropReasonUnroutable)
+			continue
+		}
+		n, seen := index[key]
+		if !seen {
+			n = len(batches)
+			index[key] = n
+			batches = append(batches, projectBatch{apiKey: key})
+		}
+		batches[n].events = append(batches[n].events, events[i])
+	}
+	return batches
+}
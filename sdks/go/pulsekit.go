@@ -3,14 +3,25 @@ package pulsekit
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Version is the version of the PulseKit Go SDK.
+const Version = "1.0.0"
+
+// defaultUserAgent identifies the SDK and Go version to the server.
+var defaultUserAgent = "pulsekit-go/" + Version + " (" + runtime.Version() + ")"
+
 // Level represents the severity level of an event.
 type Level string
 
@@ -22,6 +33,33 @@ const (
 	LevelFatal   Level = "fatal"
 )
 
+// severity returns the numeric rank of a level, higher being more severe.
+// Unknown levels rank as 0.
+func (l Level) severity() int {
+	switch l {
+	case LevelDebug:
+		return 1
+	case LevelInfo:
+		return 2
+	case LevelWarning:
+		return 3
+	case LevelError:
+		return 4
+	case LevelFatal:
+		return 5
+	}
+	return 0
+}
+
+// atLeast reports whether l is at least as severe as threshold. An empty
+// threshold never matches.
+func (l Level) atLeast(threshold Level) bool {
+	if threshold == "" {
+		return false
+	}
+	return l.severity() >= threshold.severity()
+}
+
 // Config holds the configuration for the PulseKit client.
 type Config struct {
 	// Endpoint is the PulseKit server URL
@@ -38,40 +76,437 @@ type Config struct {
 	FlushInterval time.Duration
 	// Debug enables debug logging
 	Debug bool
+	// SyncAbove sends events at or above this level synchronously instead of
+	// queueing them. Empty disables synchronous sends.
+	SyncAbove Level
+	// SyncTimeout bounds how long a synchronous send may block (default: 5s)
+	SyncTimeout time.Duration
+	// AttachProcessInfo adds a "process" context (PID, executable, redacted
+	// args, start time) to error and fatal events
+	AttachProcessInfo bool
+	// Clock provides the current time and flush ticker (default: system clock)
+	Clock Clock
+	// LevelForError chooses the level for CaptureException. By default errors
+	// implementing Severity() use that level, otherwise LevelError.
+	LevelForError func(error) Level
+	// ForceBatchEndpoint sends every request, even single events, to the
+	// batch endpoint
+	ForceBatchEndpoint bool
+	// ForceSingleEndpoint sends every event individually to the single-event
+	// endpoint, for servers without a batch route
+	ForceSingleEndpoint bool
+	// FingerprintFromRootCause groups CaptureException events by the
+	// innermost wrapped error instead of the full message
+	FingerprintFromRootCause bool
+	// SynchronousMode sends every event immediately on the calling goroutine
+	// with no batching or background flush loop. Intended for tests.
+	SynchronousMode bool
+	// AutoDetectRelease fills an empty Release from the embedded VCS
+	// revision or module version (see DetectRelease)
+	AutoDetectRelease bool
+	// MaxTags caps the number of tags per event (default: 50)
+	MaxTags int
+	// MaxMetadataKeys caps the number of top-level metadata keys per event
+	// (default: 100)
+	MaxMetadataKeys int
+	// AttachGoroutineID tags events with the ID of the goroutine that
+	// captured them ("goroutine_id"). The ID is parsed from runtime.Stack and
+	// is intended for debugging interleaved events only.
+	AttachGoroutineID bool
+	// RoutePatternFunc extracts the matched route pattern (e.g.,
+	// "/users/{id}") for Middleware. http.ServeMux patterns are detected
+	// automatically on Go 1.23+; for other routers supply a function, e.g.
+	//
+	//	chi:        func(r *http.Request) string { return chi.RouteContext(r.Context()).RoutePattern() }
+	//	gorilla/mux: func(r *http.Request) string { t, _ := mux.CurrentRoute(r).GetPathTemplate(); return t }
+	RoutePatternFunc func(*http.Request) string
+	// MaxTagCardinality caps the number of distinct values Middleware
+	// reports for each of its tags (such as "http.route"); further values
+	// are replaced with "[high-cardinality]". It guards against route
+	// extraction falling back to raw paths. Zero disables the cap.
+	MaxTagCardinality int
+	// UserAgent overrides the User-Agent header (default:
+	// "pulsekit-go/<version> (<go version>)")
+	UserAgent string
+	// Projects maps project names to API keys so one client can send to
+	// several projects over a shared transport and flush loop. Events are
+	// routed by Event.Project or ProjectRouter; events for unknown projects
+	// use APIKey.
+	Projects map[string]string
+	// ProjectRouter picks the project for events that do not set
+	// Event.Project
+	ProjectRouter func(Event) string
+	// OnDrop is called whenever an event is discarded instead of delivered,
+	// with the reason it was dropped
+	OnDrop func(event Event, reason DropReason)
+	// ForceHTTP2 sends over HTTP/2 only, including to plain http://
+	// endpoints (h2c with prior knowledge), so concurrent sends are
+	// multiplexed over one connection. It requires Go 1.24; earlier
+	// versions negotiate HTTP/2 with TLS endpoints as they do by default.
+	ForceHTTP2 bool
+	// ReportStartup captures a "pulsekit.sdk.started" event with the SDK
+	// version, Go version, environment and release when the client starts
+	ReportStartup bool
+	// SmoothDelivery spreads each flush interval's events over evenly spaced
+	// sub-batches instead of sending them all at once, smoothing server load.
+	// BatchSize still triggers an immediate flush when reached.
+	SmoothDelivery bool
+	// ContextExtractor copies application values (trace IDs, user, etc.)
+	// from a context into events captured with CaptureNow
+	ContextExtractor func(ctx context.Context, event *Event)
+	// StackFrameFilter drops stack frames for which it returns false, e.g.
+	// to hide vendored or middleware frames. It runs after the stack (at
+	// most 50 frames) is captured and before event options are applied, so
+	// WithFrameVars attaches to the first kept frame. Default: keep all.
+	StackFrameFilter func(StackFrame) bool
+	// FallbackToStderr writes events that could not be delivered to stderr
+	// as JSON lines (one event per line) so container log collectors still
+	// capture them
+	FallbackToStderr bool
+	// MaxRetries is the number of times a failed send is retried with
+	// exponential backoff (default: 0). Only network errors, 429 and 5xx
+	// responses are retried.
+	MaxRetries int
+	// EventsPerSecond limits how many events are accepted per second, with a
+	// burst of the same size. Zero disables rate limiting.
+	EventsPerSecond float64
+	// ReservedErrorBudgetFraction reserves this fraction (0-1) of the rate
+	// limit for error and fatal events, so floods of lower-severity events
+	// cannot starve them
+	ReservedErrorBudgetFraction float64
+	// PerKeyEventsPerSecond limits events captured with WithRateLimitKey
+	// per key, each key having its own bucket (with a burst of the same
+	// size) so one noisy tenant or endpoint cannot starve the others. It
+	// applies before EventsPerSecond. Zero disables per-key limiting.
+	PerKeyEventsPerSecond float64
+	// MaxRateLimitKeys bounds the number of per-key buckets kept; the least
+	// recently used bucket is evicted beyond it (default: 1000)
+	MaxRateLimitKeys int
+	// DryRun prints each request's URL and JSON payload instead of sending
+	// it. Batching, rate limiting and limits still apply, so the output
+	// matches what would be sent. Endpoint and APIKey are optional.
+	DryRun bool
+	// ContentType is the Content-Type of JSON request bodies (default:
+	// "application/json"). Ignored when Serializer is set.
+	ContentType string
+	// Serializer replaces the JSON encoding of request bodies, e.g. with
+	// msgpack. It supplies its own content type.
+	Serializer Serializer
+	// FlushBytes flushes as soon as the serialized size of queued events
+	// reaches this many bytes. Whichever of BatchSize, FlushBytes and
+	// FlushInterval is reached first triggers the flush. Zero disables it.
+	FlushBytes int
+	// UserContextKey is the context key under which the application stores
+	// the authenticated user (a User, *User, UserProvider or string ID).
+	// Defaults to the key used by ContextWithUser.
+	UserContextKey interface{}
+	// DedupeWindow drops events whose fingerprint (or type, level and
+	// message) was already sent within this window. Zero disables it.
+	DedupeWindow time.Duration
+	// MaxDedupeEntries caps the number of fingerprints remembered for
+	// DedupeWindow; the least recently sent are evicted first (default:
+	// 10000). Expired entries are also swept on every flush interval.
+	MaxDedupeEntries int
+	// MaxQueueSize bounds the number of queued events; when full, the
+	// oldest events are dropped to make room. Zero means unbounded.
+	MaxQueueSize int
+	// QueueType selects the queue implementation: QueueSlice (default) or
+	// QueueRing for high-throughput services
+	QueueType QueueType
+	// TagsByLevel adds default tags to events of a given level, e.g.
+	// {LevelError: {"alert_team": "payments"}}. Tags set on the event win.
+	TagsByLevel map[Level]map[string]string
+	// ResponseValidator inspects each response's status code and body
+	// (up to 1MB) and returns an error if the request should be treated as
+	// failed, e.g. for gateways that report errors in a 200 body. Failures
+	// are retried and written to the fallback like any other send error.
+	// Default: any non-2xx status is a failure.
+	ResponseValidator func(status int, body []byte) error
+	// SanitizeQueryParams lists query parameters (case-insensitive) whose
+	// values are redacted from request URLs captured by WithRequest and
+	// Middleware. Default: token, access_token, refresh_token, key, api_key,
+	// apikey, password, secret, signature, sig.
+	SanitizeQueryParams []string
+	// StripQueryString removes the query string from captured request URLs
+	// entirely
+	StripQueryString bool
+	// SampleRate is the fraction (0-1) of events to send. Zero is treated
+	// as 1, sending every event.
+	SampleRate float64
+	// TraceSampling makes events with a trace context (see WithTraceparent)
+	// follow the trace's sampled flag instead of SampleRate, so errors are
+	// kept exactly for sampled-in traces
+	TraceSampling bool
+	// NormalizeMessageFingerprint fingerprints events without an explicit
+	// fingerprint by their message with UUIDs, numbers, hex strings and
+	// e-mail addresses replaced by placeholders, so messages differing only
+	// in such values are grouped together
+	NormalizeMessageFingerprint bool
+	// MaxMetadataDepth limits how deeply maps, slices and structs may nest
+	// inside metadata values; deeper values and cycles are replaced with
+	// "[truncated]" and "[cycle]" markers. Zero disables the limit.
+	MaxMetadataDepth int
+	// TimestampFormat is the time layout used for event and check-in
+	// timestamps (default: time.RFC3339Nano). TimestampMillis and
+	// TimestampMicros give fixed sub-second precision; time.RFC3339 drops
+	// it, which loses the order of events within the same second.
+	TimestampFormat string
+	// OnNilError selects what CaptureException and its variants do with a
+	// nil error: NilErrorIgnore (default), NilErrorLog to print the caller's
+	// location under Debug, or NilErrorCapture to send a warning event with
+	// the caller's stack trace
+	OnNilError NilErrorPolicy
+	// OverflowSink receives events evicted from a full queue (MaxQueueSize)
+	// instead of dropping them, e.g. WriterOverflowSink for a spill file or
+	// ClientOverflowSink for a secondary endpoint. Events are dropped as
+	// usual if the sink returns an error.
+	OverflowSink OverflowSink
+	// PreserveEventFields keeps Timestamp, Environment and Release when they
+	// are already set on a captured event and only fills in blanks, for
+	// faithful replay or migration of stored events. A kept Environment is
+	// not passed through NormalizeEnvironment. Level and Origin are always
+	// only filled in when empty.
+	PreserveEventFields bool
+	// AnnotateSampling records the sample rate each kept event was sampled
+	// at, and whether it bypassed SampleRate, under the "_sampling" metadata
+	// key so the dashboard can weight sampled events
+	AnnotateSampling bool
+	// MaxDeliveryAge drops queued events older than this when they are
+	// flushed, so a backlog built up during an outage does not flood the
+	// server with stale events on recovery. Zero disables the check.
+	MaxDeliveryAge time.Duration
+	// TrackQueueLatency records how long each queued event waited before
+	// being sent under the "_queue_latency_ms" metadata key, to reveal when
+	// delivery is falling behind
+	TrackQueueLatency bool
+	// AsyncStackCapture records only program counters when an exception is
+	// captured and resolves them into frames when the event is sent, on the
+	// flush goroutine for queued events. This cuts the caller-side cost of
+	// CaptureException in hot error paths; OnDrop and OverflowSink may see
+	// events without a Stacktrace.
+	AsyncStackCapture bool
+	// NormalizeEnvironment rewrites each event's environment so variants
+	// such as "prod" and "PRODUCTION" are reported under one name. Use
+	// CanonicalEnvironment for lowercasing and common aliases. Environments
+	// kept by PreserveEventFields are not rewritten.
+	NormalizeEnvironment func(string) string
+	// RetryBudgetPerSecond caps retries across all of the client's sends
+	// with a token bucket refilled at this rate. Once it is exhausted,
+	// failed sends are not retried until it refills, so retries cannot make
+	// an outage worse. Zero means no budget.
+	RetryBudgetPerSecond float64
+	// CompactStacktrace shrinks stack traces for bandwidth-constrained
+	// clients: file paths are reduced to base names, standard library
+	// frames are dropped and repeated recursive frames are collapsed into
+	// one frame with a Count
+	CompactStacktrace bool
+	// DedupeStore replaces the in-memory store used by DedupeWindow, e.g.
+	// with a shared store so duplicates are suppressed across instances.
+	// MaxDedupeEntries only applies to the in-memory store.
+	DedupeStore DedupeStore
+	// FlushOnLevel requests an asynchronous flush of the whole queue when
+	// an event at or above this level is queued, so the events queued
+	// before an error are delivered promptly along with it
+	FlushOnLevel Level
+	// CaptureDeps lists module paths whose versions, read once from the
+	// build info at startup, are attached as the "dependencies" context to
+	// error, fatal and startup events. A path also matches the modules below
+	// it. Keep the list short to avoid bloating payloads.
+	CaptureDeps []string
+	// TenantContextKey is the context key under which the application
+	// stores the current tenant ID (a string or fmt.Stringer). CaptureNow
+	// sets TenantID and the "tenant" tag from it. Default: the key used by
+	// ContextWithTenant.
+	TenantContextKey interface{}
+	// MaxStreamBytes caps the content read from a WithMetadataStream reader
+	// (default: 10MB); longer content is cut off and marked "[truncated]"
+	MaxStreamBytes int
+	// StreamTimeout bounds sending an event with a WithMetadataStream
+	// reader, including reading the stream (default: 30s)
+	StreamTimeout time.Duration
+	// DetectSecrets scans the message and metadata string values for things
+	// that look like secrets (AWS keys, GitHub tokens, JWTs, private keys and
+	// long high-entropy strings) and replaces them with "[FILTERED]",
+	// whatever key they are stored under
+	DetectSecrets bool
+	// EnrichAtSend is called with each event on the sending goroutine just
+	// before it is encoded, to attach state that should be sampled at
+	// delivery rather than capture time (leader status, open connections,
+	// ...). It runs after every capture-time step, including DetectSecrets,
+	// and the secret filter is applied again to what it adds. Its Metadata,
+	// Tags and Contexts maps are copies the callback may modify. Panics are
+	// recovered and leave the event as captured.
+	EnrichAtSend func(*Event)
+}
+
+// severityError is implemented by errors that declare their own level.
+type severityError interface {
+	Severity() Level
+}
+
+// defaultLevelForError uses the Severity method of the first error in the
+// chain that has one, falling back to LevelError.
+func defaultLevelForError(err error) Level {
+	var se severityError
+	if errors.As(err, &se) {
+		if level := se.Severity(); level != "" {
+			return level
+		}
+	}
+	return LevelError
 }
 
 // Event represents an event to be sent to PulseKit.
 type Event struct {
-	Type        string                 `json:"type"`
-	Level       Level                  `json:"level,omitempty"`
-	Message     string                 `json:"message,omitempty"`
-	Metadata    map[string]interface{} `json:"metadata,omitempty"`
-	Stacktrace  []StackFrame           `json:"stacktrace,omitempty"`
-	Tags        map[string]string      `json:"tags,omitempty"`
-	Timestamp   string                 `json:"timestamp,omitempty"`
-	Fingerprint string                 `json:"fingerprint,omitempty"`
-	Environment string                 `json:"environment,omitempty"`
-	Release     string                 `json:"release,omitempty"`
+	Type          string                 `json:"type"`
+	Level         Level                  `json:"level,omitempty"`
+	Message       string                 `json:"message,omitempty"`
+	Metadata      map[string]interface{} `json:"metadata,omitempty"`
+	Stacktrace    []StackFrame           `json:"stacktrace,omitempty"`
+	Tags          map[string]string      `json:"tags,omitempty"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Fingerprint   string                 `json:"fingerprint,omitempty"`
+	Environment   string                 `json:"environment,omitempty"`
+	Release       string                 `json:"release,omitempty"`
+	Exception     *Exception             `json:"exception,omitempty"`
+	Contexts      map[string]interface{} `json:"contexts,omitempty"`
+	SessionID     string                 `json:"session_id,omitempty"`
+	SeverityScore *int                   `json:"severity_score,omitempty"`
+	StartTime     string                 `json:"start_time,omitempty"`
+	EndTime       string                 `json:"end_time,omitempty"`
+	Silent        bool                   `json:"silent,omitempty"`
+	Project       string                 `json:"-"`
+	User          *User                  `json:"user,omitempty"`
+	SpanLinks     []SpanLink             `json:"span_links,omitempty"`
+	Trace         *TraceContext          `json:"trace,omitempty"`
+	Origin        string                 `json:"origin,omitempty"`
+	Category      string                 `json:"category,omitempty"`
+	TenantID      string                 `json:"tenant_id,omitempty"`
+
+	// maxRetries overrides Config.MaxRetries when set by WithMaxRetries.
+	maxRetries *int
+	// lazyMetadata holds metadata producers resolved just before queueing.
+	lazyMetadata map[string]func() interface{}
+	// userCtx is the context given to WithUserFromContext.
+	userCtx context.Context
+	// request is the request given to WithRequest.
+	request *http.Request
+	// deadline is the time after which the event is dropped unsent.
+	deadline time.Time
+	// queuedAt is when the event entered the queue, for MaxDeliveryAge.
+	queuedAt time.Time
+	// pcs holds the unresolved stack of an AsyncStackCapture event, and
+	// frameVars the WithFrameVars values waiting for its top frame.
+	pcs       []uintptr
+	frameVars map[string]interface{}
+	// rateLimitKey is the key given to WithRateLimitKey.
+	rateLimitKey string
+	// forceSend exempts SDK-generated events from sampling, rate limiting
+	// and deduplication.
+	forceSend bool
+	// dedupeKey is the fingerprint recorded in the DedupeStore for the
+	// event, so the record can be undone if the event is rejected.
+	dedupeKey string
+	// stream is the metadata value given to WithMetadataStream.
+	stream *metadataStream
+	// group is the flush group given to WithFlushGroup.
+	group *FlushGroup
+	// flushAfter requests a prompt asynchronous flush once queued.
+	flushAfter bool
+	// size is the serialized size of the event, tracked for FlushBytes.
+	size int
+	// requeues counts how many times the event was returned to the queue
+	// after a partial batch failure.
+	requeues int
+}
+
+// Exception describes the error that produced an event.
+type Exception struct {
+	// Type is the Go type of the error (e.g., "*fs.PathError")
+	Type string `json:"type,omitempty"`
+	// Value is the error message
+	Value string `json:"value,omitempty"`
+	// Mechanism describes how the error was captured
+	Mechanism *Mechanism `json:"mechanism,omitempty"`
+}
+
+// Mechanism describes how an exception was captured.
+type Mechanism struct {
+	// Type identifies the capture source (e.g., "generic", "panic", "http.middleware")
+	Type string `json:"type"`
+	// Handled reports whether the error was handled by the application
+	Handled bool `json:"handled"`
+}
+
+// MechanismGeneric is the mechanism type used by plain CaptureException calls.
+const MechanismGeneric = "generic"
+
+// mergeTags returns a new map holding defaults overridden by tags.
+func mergeTags(defaults, tags map[string]string) map[string]string {
+	merged := make(map[string]string, len(defaults)+len(tags))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return merged
+}
+
+// setContext stores a named context on the event.
+func (e *Event) setContext(name string, value interface{}) {
+	if e.Contexts == nil {
+		e.Contexts = make(map[string]interface{})
+	}
+	e.Contexts[name] = value
 }
 
 // StackFrame represents a single frame in a stack trace.
 type StackFrame struct {
-	File     string `json:"file,omitempty"`
-	Line     int    `json:"line,omitempty"`
-	Function string `json:"function,omitempty"`
+	File     string                 `json:"file,omitempty"`
+	Line     int                    `json:"line,omitempty"`
+	Function string                 `json:"function,omitempty"`
+	Vars     map[string]interface{} `json:"vars,omitempty"`
+	Count    int                    `json:"count,omitempty"`
 }
 
 // Client is the PulseKit client for sending events.
 type Client struct {
-	config     Config
-	httpClient *http.Client
-	queue      []Event
-	mu         sync.Mutex
-	done       chan struct{}
-	wg         sync.WaitGroup
+	config      Config
+	httpClient  *http.Client
+	queue       eventQueue
+	queueBytes  int
+	checkIns    []CheckIn
+	mu          sync.Mutex
+	done        chan struct{}
+	flushNow    chan struct{}
+	closeOnce   sync.Once
+	wg          sync.WaitGroup
+	process     *ProcessInfo
+	deps        map[string]string
+	stats       stats
+	fallback    *fallbackWriter
+	limiter     *rateLimiter
+	keyLimiter  *keyedRateLimiter
+	retryBudget *rateLimiter
+	dedupe      DedupeStore
+	keyMu       sync.RWMutex
+	groupKey    atomic.Value
+	tagValues   tagCardinality
 }
 
-var defaultClient *Client
+var (
+	defaultClient   *Client
+	defaultClientMu sync.RWMutex
+)
+
+// getDefaultClient returns the default client, or nil if none is
+// registered.
+func getDefaultClient() *Client {
+	defaultClientMu.RLock()
+	defer defaultClientMu.RUnlock()
+	return defaultClient
+}
 
 // Init initializes the default PulseKit client.
 func Init(config Config) error {
@@ -79,19 +514,56 @@ func Init(config Config) error {
 	if err != nil {
 		return err
 	}
+	defaultClientMu.Lock()
 	defaultClient = client
+	defaultClientMu.Unlock()
 	return nil
 }
 
+// Unregister closes the default client and removes it, so package-level
+// functions become no-ops until Init is called again. It is safe to call
+// when no client is registered.
+func Unregister() {
+	defaultClientMu.Lock()
+	client := defaultClient
+	defaultClient = nil
+	defaultClientMu.Unlock()
+
+	if client != nil {
+		client.Close()
+	}
+}
+
 // NewClient creates a new PulseKit client.
 func NewClient(config Config) (*Client, error) {
-	if config.Endpoint == "" {
+	if config.Endpoint == "" && !config.DryRun {
 		return nil, fmt.Errorf("endpoint is required")
 	}
-	if config.APIKey == "" {
+	if config.APIKey == "" && len(config.Projects) == 0 && !config.DryRun {
 		return nil, fmt.Errorf("api key is required")
 	}
+	if config.ForceBatchEndpoint && config.ForceSingleEndpoint {
+		return nil, fmt.Errorf("ForceBatchEndpoint and ForceSingleEndpoint are mutually exclusive")
+	}
+	if config.ReservedErrorBudgetFraction < 0 || config.ReservedErrorBudgetFraction > 1 {
+		return nil, fmt.Errorf("ReservedErrorBudgetFraction must be between 0 and 1")
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("SampleRate must be between 0 and 1")
+	}
 
+	if config.TimestampFormat == "" {
+		config.TimestampFormat = time.RFC3339Nano
+	}
+	if config.SampleRate == 0 {
+		config.SampleRate = 1
+	}
+	if config.MaxStreamBytes <= 0 {
+		config.MaxStreamBytes = defaultMaxStreamBytes
+	}
+	if config.StreamTimeout <= 0 {
+		config.StreamTimeout = defaultStreamTimeout
+	}
 	if config.BatchSize <= 0 {
 		config.BatchSize = 10
 	}
@@ -101,54 +573,192 @@ func NewClient(config Config) (*Client, error) {
 	if config.Environment == "" {
 		config.Environment = "production"
 	}
+	if config.MaxTags <= 0 {
+		config.MaxTags = defaultMaxTags
+	}
+	if config.MaxMetadataKeys <= 0 {
+		config.MaxMetadataKeys = defaultMaxMetadataKeys
+	}
+	if config.Release == "" && config.AutoDetectRelease {
+		config.Release = DetectRelease()
+	}
+	if config.SyncTimeout <= 0 {
+		config.SyncTimeout = 5 * time.Second
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+	if config.Serializer == nil {
+		config.Serializer = jsonSerializer{contentType: config.ContentType}
+	}
+	if config.SanitizeQueryParams == nil {
+		config.SanitizeQueryParams = defaultSanitizeQueryParams
+	}
+	if config.ResponseValidator == nil {
+		config.ResponseValidator = validateStatus
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = defaultUserAgent
+	}
+	if config.Clock == nil {
+		config.Clock = realClock{}
+	}
+	if config.LevelForError == nil {
+		config.LevelForError = defaultLevelForError
+	}
 
 	c := &Client{
 		config:     config,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-		queue:      make([]Event, 0, config.BatchSize),
+		httpClient: newHTTPClient(config),
+		queue:      newEventQueue(config),
 		done:       make(chan struct{}),
+		flushNow:   make(chan struct{}, 1),
+	}
+	if config.AttachProcessInfo {
+		c.process = collectProcessInfo()
+	}
+	if len(config.CaptureDeps) > 0 {
+		c.deps = collectDeps(config.CaptureDeps)
+	}
+	if config.FallbackToStderr {
+		c.fallback = &fallbackWriter{w: os.Stderr}
+	}
+	if config.DedupeWindow > 0 {
+		if config.MaxDedupeEntries <= 0 {
+			config.MaxDedupeEntries = defaultMaxDedupeEntries
+		}
+		c.dedupe = config.DedupeStore
+		if c.dedupe == nil {
+			c.dedupe = &memoryDedupeStore{
+				cache: newDedupeCache(config.DedupeWindow, config.MaxDedupeEntries),
+				clock: config.Clock,
+			}
+		}
+	}
+	if config.EventsPerSecond > 0 {
+		c.limiter = newRateLimiter(config.EventsPerSecond, config.ReservedErrorBudgetFraction, config.Clock.Now())
+	}
+	if config.PerKeyEventsPerSecond > 0 {
+		if config.MaxRateLimitKeys <= 0 {
+			config.MaxRateLimitKeys = defaultMaxRateLimitKeys
+		}
+		c.keyLimiter = newKeyedRateLimiter(config.PerKeyEventsPerSecond, config.ReservedErrorBudgetFraction, config.MaxRateLimitKeys)
+	}
+	if config.RetryBudgetPerSecond > 0 {
+		c.retryBudget = newRateLimiter(config.RetryBudgetPerSecond, 0, config.Clock.Now())
 	}
 
-	c.wg.Add(1)
-	go c.flushLoop()
+	if !config.SynchronousMode {
+		c.wg.Add(1)
+		go c.flushLoop()
+	}
+
+	if config.ReportStartup {
+		c.reportStartup()
+	}
 
 	return c, nil
 }
 
+// reportStartup captures a "pulsekit.sdk.started" event describing the new
+// client instance. It is force-sent, bypassing sampling, rate limiting and
+// deduplication.
+func (c *Client) reportStartup() {
+	c.Capture(Event{
+		Type:    "pulsekit.sdk.started",
+		Level:   LevelInfo,
+		Message: "PulseKit SDK started",
+		Origin:  OriginSDK,
+		Metadata: map[string]interface{}{
+			"sdk_version": Version,
+			"go_version":  runtime.Version(),
+			"environment": c.config.Environment,
+			"release":     c.config.Release,
+		},
+		forceSend: true,
+	})
+}
+
 // CaptureException captures an error with stack trace.
 func CaptureException(err error, opts ...EventOption) {
-	if defaultClient == nil {
+	client := getDefaultClient()
+	if client == nil {
 		return
 	}
-	defaultClient.CaptureException(err, opts...)
+	client.CaptureException(err, opts...)
 }
 
 // CaptureException captures an error with stack trace.
 func (c *Client) CaptureException(err error, opts ...EventOption) {
 	if err == nil {
+		c.nilError(opts)
+		return
+	}
+	c.captureException(err, capturePCs(3), opts)
+}
+
+// CaptureExceptionWithLocals captures an error using the default client and
+// attaches locals to the top stack frame.
+func CaptureExceptionWithLocals(err error, locals map[string]interface{}, opts ...EventOption) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CaptureExceptionWithLocals(err, locals, opts...)
+}
+
+// CaptureExceptionWithLocals captures an error with stack trace and attaches
+// the named local variables to the top frame's Vars. Values whose names look
+// like secrets (password, token, key, ...) are redacted. It is intended for
+// deferred recover handlers and other places where the developer knows which
+// locals explain the failure.
+func (c *Client) CaptureExceptionWithLocals(err error, locals map[string]interface{}, opts ...EventOption) {
+	if err == nil {
+		c.nilError(opts)
 		return
 	}
+	opts = append([]EventOption{WithFrameVars(redactVars(locals))}, opts...)
+	c.captureException(err, capturePCs(3), opts)
+}
 
+// captureException builds an error event for err with the stack trace
+// recorded in pcs, applies opts and enqueues it. With AsyncStackCapture the
+// frames are resolved when the event is sent rather than here.
+func (c *Client) captureException(err error, pcs []uintptr, opts []EventOption) {
 	event := Event{
-		Type:       "error",
-		Level:      LevelError,
-		Message:    err.Error(),
-		Stacktrace: captureStackTrace(3),
+		Type:    "error",
+		Level:   c.config.LevelForError(err),
+		Message: err.Error(),
+		Exception: &Exception{
+			Type:      exceptionType(err),
+			Value:     err.Error(),
+			Mechanism: &Mechanism{Type: MechanismGeneric, Handled: true},
+		},
+	}
+	if c.config.AsyncStackCapture {
+		event.pcs = pcs
+	} else {
+		event.Stacktrace = c.buildStack(pcs)
+	}
+	if c.config.FingerprintFromRootCause {
+		event.Fingerprint = rootCauseFingerprint(err)
 	}
 
 	for _, opt := range opts {
 		opt(&event)
 	}
+	applyErrorFields(err, &event)
 
 	c.enqueue(event)
 }
 
 // Capture sends a custom event.
 func Capture(event Event) {
-	if defaultClient == nil {
+	client := getDefaultClient()
+	if client == nil {
 		return
 	}
-	defaultClient.Capture(event)
+	client.Capture(event)
 }
 
 // Capture sends a custom event.
@@ -156,12 +766,116 @@ func (c *Client) Capture(event Event) {
 	c.enqueue(event)
 }
 
+// CaptureRaw sends an event exactly as given using the default client.
+func CaptureRaw(event Event) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CaptureRaw(event)
+}
+
+// CaptureRaw sends an event without any enrichment: Timestamp, Environment,
+// Release and Level are not filled in, and no contexts or automatic tags are
+// added. This is intended for import and replay tooling that already has
+// complete events. Validation (MaxTags, MaxMetadataKeys) and delivery
+// settings (batching, SyncAbove, SynchronousMode) still apply.
+func (c *Client) CaptureRaw(event Event) {
+	c.submit(event)
+}
+
+// TryCapture captures an event using the default client and reports whether
+// it was accepted.
+func TryCapture(event Event) bool {
+	client := getDefaultClient()
+	if client == nil {
+		return false
+	}
+	return client.TryCapture(event)
+}
+
+// TryCapture is like Capture but reports whether the event was accepted.
+// It returns false if the event was sampled out, deduplicated or rate
+// limited, or if the queue is at MaxQueueSize; unlike Capture, a full queue
+// rejects the new event rather than evicting the oldest one. Callers can use
+// the result to back off while the SDK is shedding load.
+func (c *Client) TryCapture(event Event) bool {
+	if !c.prepare(&event) {
+		return false
+	}
+	c.finalize(&event)
+	if event.stream != nil {
+		c.sendStream(event)
+		return true
+	}
+	if event.group != nil {
+		event.group.add(event)
+		return true
+	}
+	if c.sendsSync(event) {
+		c.sendSync([]Event{event})
+		return true
+	}
+	if !c.queueEvents([]Event{event}, true) {
+		c.forgetDuplicate(event)
+		return false
+	}
+	return true
+}
+
+// CaptureBatch captures events using the default client and returns how
+// many were accepted.
+func CaptureBatch(events []Event) int {
+	client := getDefaultClient()
+	if client == nil {
+		return 0
+	}
+	return client.CaptureBatch(events)
+}
+
+// CaptureBatch captures many events at once, e.g. when importing or
+// replaying. Each event is enriched and filtered exactly as by Capture, but
+// the accepted events are queued under a single lock and a flush is
+// triggered at most once. It returns the number of events accepted after
+// filtering (deduplication, rate limiting).
+func (c *Client) CaptureBatch(events []Event) int {
+	var queued, sync []Event
+	handled := 0
+	for _, event := range events {
+		if !c.prepare(&event) {
+			continue
+		}
+		c.finalize(&event)
+		switch {
+		case event.stream != nil:
+			c.sendStream(event)
+			handled++
+		case event.group != nil:
+			event.group.add(event)
+			handled++
+		case c.sendsSync(event):
+			sync = append(sync, event)
+		default:
+			queued = append(queued, event)
+		}
+	}
+
+	if len(sync) > 0 {
+		c.sendSync(sync)
+	}
+	if len(queued) > 0 {
+		c.queueEvents(queued, false)
+	}
+	return handled + len(sync) + len(queued)
+}
+
 // CaptureMessage sends a simple message event.
 func CaptureMessage(message string, level Level, opts ...EventOption) {
-	if defaultClient == nil {
+	client := getDefaultClient()
+	if client == nil {
 		return
 	}
-	defaultClient.CaptureMessage(message, level, opts...)
+	client.CaptureMessage(message, level, opts...)
 }
 
 // CaptureMessage sends a simple message event.
@@ -181,68 +895,224 @@ func (c *Client) CaptureMessage(message string, level Level, opts ...EventOption
 
 // Flush sends all queued events immediately.
 func Flush() {
-	if defaultClient == nil {
+	client := getDefaultClient()
+	if client == nil {
 		return
 	}
-	defaultClient.Flush()
+	client.Flush()
 }
 
 // Flush sends all queued events immediately.
 func (c *Client) Flush() {
+	c.flush()
+}
+
+// flush sends all queued events and check-ins, returning an error
+// describing anything that could not be delivered.
+func (c *Client) flush() error {
 	c.mu.Lock()
-	events := c.queue
-	c.queue = make([]Event, 0, c.config.BatchSize)
+	events := c.popLocked(-1)
 	c.mu.Unlock()
 
+	var result sendResult
 	if len(events) > 0 {
-		c.sendEvents(events)
+		result = c.sendEvents(context.Background(), events)
+	}
+	if err := c.flushCheckIns(context.Background()); err != nil {
+		return errors.Join(result.error(), fmt.Errorf("check-ins undelivered: %w", err))
 	}
+	return result.error()
 }
 
-// Close flushes remaining events and stops the client.
-func Close() {
-	if defaultClient == nil {
-		return
+// Close flushes remaining events and stops the default client, returning an
+// error if the final flush could not deliver everything.
+func Close() error {
+	client := getDefaultClient()
+	if client == nil {
+		return nil
 	}
-	defaultClient.Close()
+	return client.Close()
 }
 
-// Close flushes remaining events and stops the client.
-func (c *Client) Close() {
-	close(c.done)
-	c.wg.Wait()
-	c.Flush()
+// Close flushes remaining events and stops the client. Events the server
+// rejects in a partially failed final batch are retried before Close
+// returns, and dropped with DropReasonRejected if they are still rejected.
+// The error reports how many events could not be delivered (including
+// events written to stderr by FallbackToStderr or dropped as rejected)
+// along with the last send error, and whether queued check-ins could not
+// be delivered. Calling Close more than once has no effect and returns nil.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.wg.Wait()
+		err = c.flush()
+		if drainErr := c.drainRequeued(context.Background()); drainErr != nil {
+			err = errors.Join(err, drainErr)
+		}
+	})
+	return err
 }
 
 func (c *Client) enqueue(event Event) {
-	event.Timestamp = time.Now().UTC().Format(time.RFC3339)
-	event.Environment = c.config.Environment
-	if c.config.Release != "" {
+	if c.prepare(&event) {
+		c.submit(event)
+	}
+}
+
+// prepare fills in defaults and enrichment for a captured event. It returns
+// false if the event was filtered out.
+func (c *Client) prepare(event *Event) bool {
+	preserve := c.config.PreserveEventFields
+	if !preserve || event.Timestamp == "" {
+		event.Timestamp = c.timestamp()
+	}
+	if !preserve || event.Environment == "" {
+		event.Environment = c.config.Environment
+		if c.config.NormalizeEnvironment != nil {
+			event.Environment = c.config.NormalizeEnvironment(event.Environment)
+		}
+	}
+	if c.config.Release != "" && (!preserve || event.Release == "") {
 		event.Release = c.config.Release
 	}
 	if event.Level == "" {
 		event.Level = LevelInfo
 	}
+	if event.Origin == "" {
+		event.Origin = OriginManual
+	}
+	if c.config.NormalizeMessageFingerprint && event.Fingerprint == "" && event.Message != "" {
+		event.Fingerprint = messageFingerprint(event)
+	}
+	// Deduplication comes last so that only accepted events are recorded
+	// as sent.
+	if c.sampledOut(event) || (!event.forceSend && (c.rateLimited(*event) || c.duplicate(event))) {
+		return false
+	}
+	// The group key override is applied only after deduplication, which
+	// would otherwise drop every error of the storm after the first.
+	if key := c.groupKeyOverride(); key != "" && event.Level.atLeast(LevelError) {
+		event.Fingerprint = key
+	}
+	c.applyUserContext(event)
+	c.applyRequest(event)
+	if levelTags := c.config.TagsByLevel[event.Level]; len(levelTags) > 0 {
+		event.Tags = mergeTags(levelTags, event.Tags)
+	}
+	if c.process != nil && event.Level.atLeast(LevelError) {
+		event.setContext("process", c.process)
+	}
+	if c.deps != nil && (event.Level.atLeast(LevelError) || event.Origin == OriginSDK) {
+		event.setContext("dependencies", c.deps)
+	}
+	if c.config.AttachGoroutineID {
+		if id := goroutineID(); id != "" {
+			event.Tags = mergeTags(event.Tags, map[string]string{"goroutine_id": id})
+		}
+	}
+	return true
+}
+
+// submit validates an event and hands it to the transport, either sending it
+// synchronously or appending it to the queue.
+func (c *Client) submit(event Event) {
+	c.finalize(&event)
+
+	if event.stream != nil {
+		c.sendStream(event)
+		return
+	}
+	if event.group != nil {
+		event.group.add(event)
+		return
+	}
+	if c.sendsSync(event) {
+		c.sendSync([]Event{event})
+		return
+	}
+	c.queueEvents([]Event{event}, false)
+}
+
+// finalize resolves deferred metadata and applies limits just before an
+// event is sent or queued.
+func (c *Client) finalize(event *Event) {
+	if !c.config.AsyncStackCapture {
+		c.resolveStack(event)
+	}
+	c.resolveLazyMetadata(event)
+	encodeBinaryMetadata(event)
+	c.validateRawMetadata(event)
+	c.applyDepthLimit(event)
+	c.applyKeyLimits(event)
+	c.filterSecrets(event)
+}
+
+// sendsSync reports whether event bypasses the queue.
+func (c *Client) sendsSync(event Event) bool {
+	return c.config.SynchronousMode || event.Level.atLeast(c.config.SyncAbove)
+}
+
+// sendSync sends events immediately, bounded by Config.SyncTimeout.
+func (c *Client) sendSync(events []Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.SyncTimeout)
+	defer cancel()
+	c.sendEvents(ctx, events)
+}
+
+// queueEvents appends events to the queue under a single lock and flushes
+// if a threshold was reached. When the queue is full, the oldest events are
+// evicted, or, with rejectWhenFull, the new events are dropped instead and
+// false is returned.
+func (c *Client) queueEvents(events []Event, rejectWhenFull bool) bool {
+	flushAfter := false
+	now := c.config.Clock.Now()
+	for i := range events {
+		events[i].queuedAt = now
+		if c.config.FlushBytes > 0 {
+			events[i].size = c.encodedSize(events[i])
+		}
+		flushAfter = flushAfter || events[i].flushAfter || events[i].Level.atLeast(c.config.FlushOnLevel)
+	}
 
 	c.mu.Lock()
-	c.queue = append(c.queue, event)
-	shouldFlush := len(c.queue) >= c.config.BatchSize
+	if rejectWhenFull && c.config.MaxQueueSize > 0 && c.queue.len()+len(events) > c.config.MaxQueueSize {
+		c.mu.Unlock()
+		for _, event := range events {
+			c.drop(event, DropReasonQueueFull)
+		}
+		return false
+	}
+	evicted := c.pushLocked(events...)
+	shouldFlush := c.shouldFlushLocked()
 	c.mu.Unlock()
+	c.reportEvicted(evicted)
 
 	if shouldFlush {
 		c.Flush()
+	} else if flushAfter {
+		c.requestFlush()
 	}
+	return true
 }
 
 func (c *Client) flushLoop() {
 	defer c.wg.Done()
 
-	ticker := time.NewTicker(c.config.FlushInterval)
+	if c.config.SmoothDelivery {
+		c.smoothFlushLoop()
+		return
+	}
+
+	ticker := c.config.Clock.NewTicker(c.config.FlushInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
+			c.Flush()
+			c.sweepCaches()
+		case <-c.flushNow:
 			c.Flush()
 		case <-c.done:
 			return
@@ -250,11 +1120,65 @@ func (c *Client) flushLoop() {
 	}
 }
 
-func (c *Client) sendEvents(events []Event) {
+// sweepCaches evicts expired entries from time-windowed caches.
+func (c *Client) sweepCaches() {
+	if cache := c.memoryDedupe(); cache != nil {
+		cache.sweep(c.config.Clock.Now())
+	}
+}
+
+// requestFlush asks the flush loop to flush soon without blocking the
+// caller. Requests made while one is pending are coalesced into a single
+// flush.
+func (c *Client) requestFlush() {
+	select {
+	case c.flushNow <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Client) sendEvents(ctx context.Context, events []Event) sendResult {
+	var result sendResult
+	events = c.dropExpired(events)
+	if len(events) == 0 {
+		return result
+	}
+	if c.config.TrackQueueLatency {
+		c.annotateQueueLatency(events)
+	}
+	c.resolveStacks(events)
+	c.enrichAtSend(events)
+
+	start := c.config.Clock.Now()
+	defer func() {
+		c.stats.recordFlush(len(events), c.config.Clock.Now().Sub(start))
+	}()
+
+	for _, batch := range c.groupByProject(events) {
+		if c.config.ForceSingleEndpoint && len(batch.events) > 1 {
+			for _, event := range batch.events {
+				result.add(c.postEvents(ctx, batch.apiKey, []Event{event}))
+			}
+			continue
+		}
+		result.add(c.postEvents(ctx, batch.apiKey, batch.events))
+	}
+	return result
+}
+
+// postEvents sends events in a single request, choosing the single-event or
+// batch endpoint.
+func (c *Client) postEvents(ctx context.Context, apiKey string, events []Event) sendResult {
+	return c.postTo(ctx, apiKey, events, len(events) > 1 || c.config.ForceBatchEndpoint)
+}
+
+// postTo sends events to the batch endpoint, or the single-event endpoint
+// when batch is false (events must then hold exactly one event).
+func (c *Client) postTo(ctx context.Context, apiKey string, events []Event, batch bool) sendResult {
 	var url string
 	var body interface{}
 
-	if len(events) == 1 {
+	if !batch {
 		url = c.config.Endpoint + "/api/v1/events"
 		body = events[0]
 	} else {
@@ -262,45 +1186,326 @@ func (c *Client) sendEvents(events []Event) {
 		body = map[string]interface{}{"events": events}
 	}
 
-	jsonBody, err := json.Marshal(body)
+	rejected := 0
+	err := c.withRetries(ctx, c.maxRetriesFor(events), func() error {
+		return c.post(ctx, url, apiKey, body, "events", func(resp *http.Response) {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Sent %d event(s), status: %d\n", len(events), resp.StatusCode)
+			}
+
+			if batch && resp.StatusCode == http.StatusMultiStatus {
+				rejected = c.handlePartialFailure(events, resp.Body)
+			}
+		})
+	})
 	if err != nil {
+		c.recordFailure(err)
+	}
+	var encErr *encodeError
+	if errors.As(err, &encErr) {
+		return c.dropUnmarshalable(ctx, apiKey, events, err)
+	}
+	if payloadRejected(err) {
+		if batch {
+			return c.isolateEvents(ctx, apiKey, events)
+		}
 		if c.config.Debug {
-			fmt.Printf("[PulseKit] Failed to marshal events: %v\n", err)
+			fmt.Printf("[PulseKit] Dropping %q event rejected by the server: %v\n", events[0].Type, err)
 		}
+		c.drop(events[0], DropReasonRejected)
+		return sendResult{undelivered: 1, err: err}
+	}
+	if err != nil {
+		c.failEvents(events)
+		return sendResult{undelivered: len(events), err: err}
+	}
+	if rejected > 0 {
+		return sendResult{undelivered: rejected, err: errRejected}
+	}
+	return sendResult{}
+}
+
+// failEvents hands events that could not be delivered to the fallback, or
+// drops them if there is none.
+func (c *Client) failEvents(events []Event) {
+	if c.fallback != nil {
+		c.writeFallback(events)
 		return
 	}
+	for _, event := range events {
+		c.drop(event, DropReasonSendFailed)
+	}
+}
+
+// payloadRejected reports whether the server refused the request body
+// itself (400 Bad Request or 413 Payload Too Large), so resending the same
+// payload cannot succeed.
+func payloadRejected(err error) bool {
+	var se *statusError
+	return errors.As(err, &se) && (se.code == http.StatusBadRequest || se.code == http.StatusRequestEntityTooLarge)
+}
+
+// isolateEvents resends the events of a rejected batch one by one to the
+// single-event endpoint, so one malformed or oversized event cannot prevent
+// the others from being delivered. Events rejected on their own are
+// dropped.
+func (c *Client) isolateEvents(ctx context.Context, apiKey string, events []Event) sendResult {
+	if c.config.Debug {
+		fmt.Printf("[PulseKit] Batch of %d event(s) rejected, sending individually\n", len(events))
+	}
+	var result sendResult
+	for _, event := range events {
+		result.add(c.postTo(ctx, apiKey, []Event{event}, false))
+	}
+	return result
+}
+
+// dropUnmarshalable marshals events one by one after a batch failed to
+// encode with err, drops and logs the offending events, and sends the rest.
+func (c *Client) dropUnmarshalable(ctx context.Context, apiKey string, events []Event, err error) sendResult {
+	valid := make([]Event, 0, len(events))
+	for _, event := range events {
+		if _, err := c.config.Serializer.Serialize(io.Discard, event); err != nil {
+			if c.config.Debug {
+				fmt.Printf("[PulseKit] Dropping %q event that failed to marshal: %v\n", event.Type, err)
+			}
+			c.drop(event, DropReasonInvalid)
+			continue
+		}
+		valid = append(valid, event)
+	}
+	result := sendResult{undelivered: len(events) - len(valid), err: err}
+	if len(valid) > 0 && len(valid) < len(events) {
+		result.add(c.postEvents(ctx, apiKey, valid))
+	}
+	return result
+}
+
+// sendResult records the events a send failed to deliver.
+type sendResult struct {
+	undelivered int
+	// err is the last send error
+	err error
+}
+
+func (r *sendResult) add(other sendResult) {
+	r.undelivered += other.undelivered
+	if other.err != nil {
+		r.err = other.err
+	}
+}
+
+// error returns nil if everything was delivered.
+func (r sendResult) error() error {
+	if r.undelivered == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d event(s) undelivered: %w", r.undelivered, r.err)
+}
+
+// encodeError reports that a request body could not be marshaled.
+type encodeError struct {
+	err error
+}
+
+func (e *encodeError) Error() string { return "encode: " + e.err.Error() }
+func (e *encodeError) Unwrap() error { return e.err }
+
+// statusError reports a non-2xx response from the server.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.code)
+}
+
+// validateStatus is the default ResponseValidator: any non-2xx status is a
+// failure.
+func validateStatus(status int, _ []byte) error {
+	if status < 200 || status > 299 {
+		return &statusError{code: status}
+	}
+	return nil
+}
+
+// maxResponseBodySize bounds how much of a response body is read for
+// ResponseValidator and response handlers.
+const maxResponseBodySize = 1 << 20
+
+// post encodes body with the configured Serializer and POSTs it to url authenticated with apiKey.
+// If the response is accepted, handle is called with it
+// before its body is closed. what names the payload in debug output.
+// It returns an error if the request could not be made or the response was
+// rejected by Config.ResponseValidator.
+func (c *Client) post(ctx context.Context, url, apiKey string, body interface{}, what string, handle func(*http.Response)) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	contentType, err := c.config.Serializer.Serialize(buf, body)
+	if err != nil {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Failed to marshal %s: %v\n", what, err)
+		}
+		return &encodeError{err: err}
+	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewReader(jsonBody))
+	if c.config.DryRun {
+		fmt.Printf("[PulseKit] Dry run: POST %s\n%s", url, buf.Bytes())
+		return nil
+	}
+
+	return c.do(ctx, url, apiKey, contentType, bytes.NewReader(buf.Bytes()), what, handle)
+}
+
+// do POSTs body to url authenticated with apiKey and checks the response
+// with Config.ResponseValidator. If the validator accepts the response,
+// handle is called with it before its body is closed.
+func (c *Client) do(ctx context.Context, url, apiKey, contentType string, body io.Reader, what string, handle func(*http.Response)) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, body)
 	if err != nil {
 		if c.config.Debug {
 			fmt.Printf("[PulseKit] Failed to create request: %v\n", err)
 		}
-		return
+		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-PulseKit-Key", c.config.APIKey)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-PulseKit-Key", apiKey)
+	req.Header.Set("User-Agent", c.config.UserAgent)
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		if c.config.Debug {
-			fmt.Printf("[PulseKit] Failed to send events: %v\n", err)
+			fmt.Printf("[PulseKit] Failed to send %s: %v\n", what, err)
+		}
+		return err
+	}
+	defer func(body io.ReadCloser) {
+		// Drain the body so the connection can be reused.
+		io.Copy(io.Discard, body)
+		body.Close()
+	}(resp.Body)
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Failed to read %s response: %v\n", what, err)
 		}
+		return err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	// Validate first: handle may act on the response (re-queueing the
+	// rejected events of a 207), which must not happen when the request is
+	// about to be retried as a whole.
+	if err := c.config.ResponseValidator(resp.StatusCode, respBody); err != nil {
+		return err
+	}
+	handle(resp)
+	return nil
+}
+
+// resolveStacks symbolizes the stacks of AsyncStackCapture events and
+// attaches any pending WithFrameVars values to their top frame.
+func (c *Client) resolveStacks(events []Event) {
+	for i := range events {
+		c.resolveStack(&events[i])
+	}
+}
+
+// resolveStack symbolizes event's unresolved stack, if it has one.
+func (c *Client) resolveStack(event *Event) {
+	if event.pcs == nil {
 		return
 	}
-	defer resp.Body.Close()
+	event.Stacktrace = c.buildStack(event.pcs)
+	event.pcs = nil
+	if event.frameVars != nil {
+		vars := event.frameVars
+		event.frameVars = nil
+		WithFrameVars(vars)(event)
+	}
+}
 
-	if c.config.Debug {
-		fmt.Printf("[PulseKit] Sent %d event(s), status: %d\n", len(events), resp.StatusCode)
+// buildStack resolves pcs into frames, applying StackFrameFilter and
+// CompactStacktrace.
+func (c *Client) buildStack(pcs []uintptr) []StackFrame {
+	frames := c.filterStack(resolveFrames(pcs))
+	if c.config.CompactStacktrace {
+		frames = compactStack(frames)
 	}
+	return frames
 }
 
-func captureStackTrace(skip int) []StackFrame {
-	var frames []StackFrame
-	pcs := make([]uintptr, 50)
+// filterStack removes frames rejected by Config.StackFrameFilter.
+func (c *Client) filterStack(frames []StackFrame) []StackFrame {
+	if c.config.StackFrameFilter == nil {
+		return frames
+	}
+	kept := frames[:0]
+	for _, frame := range frames {
+		if c.config.StackFrameFilter(frame) {
+			kept = append(kept, frame)
+		}
+	}
+	return kept
+}
+
+// bufferPool reuses encoding buffers across sends to reduce allocations.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledBufferSize keeps unusually large buffers from pinning memory in
+// the pool.
+const maxPooledBufferSize = 1 << 20
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+	bufferPool.Put(buf)
+}
+
+// maxStackFrames is the maximum number of frames captured per stack trace.
+const maxStackFrames = 50
+
+// sdkPackage is the import path of this package, for recognizing SDK frames.
+const sdkPackage = "github.com/pulsekit/go"
+
+// capturePCs records the program counters of the calling goroutine's stack,
+// skipping skip frames as runtime.Callers does. This is the cheap half of
+// stack capture; resolveFrames does the symbolization.
+func capturePCs(skip int) []uintptr {
+	pcs := make([]uintptr, maxStackFrames)
 	n := runtime.Callers(skip, pcs)
-	pcs = pcs[:n]
+	return pcs[:n]
+}
+
+// trimSDKFrames drops the leading program counters that belong to this
+// package, so a stack captured inside an EventOption starts at the caller of
+// the SDK.
+func trimSDKFrames(pcs []uintptr) []uintptr {
+	for len(pcs) > 0 {
+		fn := runtime.FuncForPC(pcs[0] - 1)
+		if fn == nil || !strings.HasPrefix(fn.Name(), sdkPackage+".") {
+			break
+		}
+		pcs = pcs[1:]
+	}
+	return pcs
+}
 
+// resolveFrames symbolizes program counters into stack frames.
+func resolveFrames(pcs []uintptr) []StackFrame {
+	var frames []StackFrame
 	callersFrames := runtime.CallersFrames(pcs)
 	for {
 		frame, more := callersFrames.Next()
@@ -332,6 +1537,14 @@ func WithTags(tags map[string]string) EventOption {
 	}
 }
 
+// WithTagsIf adds tags to an event only when cond is true.
+func WithTagsIf(cond bool, tags map[string]string) EventOption {
+	if !cond {
+		return func(*Event) {}
+	}
+	return WithTags(tags)
+}
+
 // WithMetadata adds metadata to an event.
 func WithMetadata(metadata map[string]interface{}) EventOption {
 	return func(e *Event) {
@@ -351,6 +1564,14 @@ func WithType(eventType string) EventOption {
 	}
 }
 
+// WithCategory sets a coarse classification for the event, such as
+// "database", "network" or "auth", independent of its type.
+func WithCategory(category string) EventOption {
+	return func(e *Event) {
+		e.Category = category
+	}
+}
+
 // WithLevel sets the event level.
 func WithLevel(level Level) EventOption {
 	return func(e *Event) {
@@ -365,3 +1586,104 @@ func WithFingerprint(fingerprint string) EventOption {
 	}
 }
 
+// WithMechanism sets how the event's exception was captured. It has no effect
+// on events without an exception.
+func WithMechanism(mechanismType string, handled bool) EventOption {
+	return func(e *Event) {
+		if e.Exception == nil {
+			return
+		}
+		e.Exception.Mechanism = &Mechanism{Type: mechanismType, Handled: handled}
+	}
+}
+
+// WithSession links the event to a frontend session or replay ID.
+func WithSession(id string) EventOption {
+	return func(e *Event) {
+		e.SessionID = id
+	}
+}
+
+// WithSeverityScore sets a numeric severity between 0 and 100. Values outside
+// that range are clamped.
+func WithSeverityScore(score int) EventOption {
+	if score < 0 {
+		score = 0
+	} else if score > 100 {
+		score = 100
+	}
+	return func(e *Event) {
+		s := score
+		e.SeverityScore = &s
+	}
+}
+
+// WithDuration records the window an event's operation spanned. Unlike
+// Timestamp, these fields are never overwritten when the event is queued.
+func WithDuration(start, end time.Time) EventOption {
+	return func(e *Event) {
+		e.StartTime = start.UTC().Format(time.RFC3339Nano)
+		e.EndTime = end.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// WithSilent marks the event as archival: it is stored but the server skips
+// alert rule evaluation for it.
+func WithSilent() EventOption {
+	return func(e *Event) {
+		e.Silent = true
+	}
+}
+
+// WithFrameVars attaches known local variable values to the top frame of the
+// event's stack trace. Go cannot inspect locals at runtime, so callers pass
+// the values they care about. It has no effect on events without a stack
+// trace.
+func WithFrameVars(vars map[string]interface{}) EventOption {
+	return func(e *Event) {
+		if len(e.Stacktrace) == 0 && e.pcs != nil {
+			if e.frameVars == nil {
+				e.frameVars = make(map[string]interface{}, len(vars))
+			}
+			for k, v := range vars {
+				e.frameVars[k] = v
+			}
+			return
+		}
+		if len(e.Stacktrace) == 0 {
+			return
+		}
+		frame := &e.Stacktrace[0]
+		if frame.Vars == nil {
+			frame.Vars = make(map[string]interface{}, len(vars))
+		}
+		for k, v := range vars {
+			frame.Vars[k] = v
+		}
+	}
+}
+
+// WithAttachStack records the stack of the calling goroutine on the event,
+// giving provenance to non-error events such as warnings or suspicious state
+// transitions. Frames inside the SDK are left out, and the stack is limited,
+// filtered and compacted like those of CaptureException. Events that already
+// have a stack trace are unchanged. Capturing a stack is relatively costly,
+// so it is opt-in per event; apply it before WithFrameVars.
+func WithAttachStack() EventOption {
+	return func(e *Event) {
+		if len(e.Stacktrace) > 0 || e.pcs != nil {
+			return
+		}
+		e.pcs = trimSDKFrames(capturePCs(3))
+	}
+}
+
+// WithFlushAfter requests an asynchronous flush as soon as the event is
+// queued, for prompt delivery without blocking the caller. Flush requests
+// made while one is already pending are coalesced, so applying it to many
+// events does not cause a flush storm.
+func WithFlushAfter() EventOption {
+	return func(e *Event) {
+		e.flushAfter = true
+	}
+}
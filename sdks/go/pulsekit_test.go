@@ -0,0 +1,172 @@
+package pulsekit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// discardTransport answers every request with an empty 200 response without
+// touching the network, so benchmarks measure only the client.
+type discardTransport struct{}
+
+func (discardTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	io.Copy(io.Discard, r.Body)
+	r.Body.Close()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    r,
+	}, nil
+}
+
+// BenchmarkSendEvents measures encoding and sending a batch. Request buffers
+// are reused through bufferPool rather than allocated for every request.
+func BenchmarkSendEvents(b *testing.B) {
+	c, err := NewClient(Config{Endpoint: "http://pulsekit.invalid", APIKey: "test-key"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+	c.httpClient.Transport = discardTransport{}
+
+	events := make([]Event, 10)
+	for i := range events {
+		events[i] = sizedEvent(i)
+		c.prepare(&events[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.sendEvents(context.Background(), events)
+	}
+}
+
+// BenchmarkCaptureException measures the latency CaptureException adds to
+// the caller, with stacks symbolized on the calling goroutine or deferred to
+// the sender by AsyncStackCapture.
+func BenchmarkCaptureException(b *testing.B) {
+	for _, async := range []bool{false, true} {
+		b.Run(fmt.Sprintf("AsyncStackCapture=%v", async), func(b *testing.B) {
+			const queued = 1000
+			c, err := NewClient(Config{
+				Endpoint:          "http://pulsekit.invalid",
+				APIKey:            "test-key",
+				BatchSize:         queued + 1,
+				FlushInterval:     time.Hour,
+				AsyncStackCapture: async,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer c.Close()
+			c.httpClient.Transport = discardTransport{}
+			err = errors.New("benchmark error")
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				c.CaptureException(err)
+				if i%queued == queued-1 {
+					b.StopTimer()
+					c.reset()
+					b.StartTimer()
+				}
+			}
+		})
+	}
+}
+
+// TestResponseValidatorBeforePartialFailure checks that a 207 response
+// failed by ResponseValidator does not re-queue its rejected events: the
+// whole batch is retried instead, so nothing is sent twice.
+func TestResponseValidatorBeforePartialFailure(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch struct {
+			Events []Event `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&batch)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, event := range batch.Events {
+			sent = append(sent, event.Message)
+		}
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusMultiStatus)
+			json.NewEncoder(w).Encode(batchResponse{Accepted: []int{0}, Rejected: []int{1}})
+		}
+	}))
+	defer ts.Close()
+
+	validated := 0
+	c, err := NewClient(Config{
+		Endpoint:      ts.URL,
+		APIKey:        "test-key",
+		MaxRetries:    1,
+		FlushInterval: time.Hour,
+		ResponseValidator: func(status int, body []byte) error {
+			validated++
+			if validated == 1 {
+				return errors.New("gateway error")
+			}
+			return validateStatus(status, body)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.CaptureMessage("first", LevelInfo)
+	c.CaptureMessage("second", LevelInfo)
+	c.Flush()
+
+	if n := c.queue.len(); n != 0 {
+		t.Errorf("%d event(s) re-queued after the batch was retried", n)
+	}
+	if want := []string{"first", "second", "first", "second"}; fmt.Sprint(sent) != fmt.Sprint(want) {
+		t.Errorf("sent %v, want %v", sent, want)
+	}
+}
+
+// TestSendFailedDrop checks that events still undelivered after all retries
+// are reported to OnDrop when there is no fallback.
+func TestSendFailedDrop(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var dropped []DropReason
+	c, err := NewClient(Config{
+		Endpoint:        ts.URL,
+		APIKey:          "test-key",
+		SynchronousMode: true,
+		OnDrop: func(event Event, reason DropReason) {
+			dropped = append(dropped, reason)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	c.CaptureMessage("lost", LevelInfo, WithMaxRetries(0))
+
+	if len(dropped) != 1 || dropped[0] != DropReasonSendFailed {
+		t.Errorf("dropped = %v, want [%s]", dropped, DropReasonSendFailed)
+	}
+}
@@ -0,0 +1,241 @@
+package pulsekit
+
+import "fmt"
+
+// QueueType selects the data structure that holds queued events.
+type QueueType string
+
+const (
+	// QueueSlice stores events in a slice that is replaced on every flush.
+	// It is the default.
+	QueueSlice QueueType = "slice"
+	// QueueRing stores events in a reusable ring buffer, avoiding
+	// reallocation under sustained load. With MaxQueueSize set, its storage
+	// is allocated once up front.
+	QueueRing QueueType = "ring"
+)
+
+// eventQueue is a FIFO of events. Implementations bounded by a maximum size
+// evict the oldest events to make room and return them to the caller.
+type eventQueue interface {
+	len() int
+	// push appends events to the back.
+	push(events ...Event) (evicted []Event)
+	// pushFront inserts events at the front, preserving their order.
+	pushFront(events ...Event) (evicted []Event)
+	// pop removes up to n events from the front; a negative n removes all.
+	pop(n int) []Event
+}
+
+func newEventQueue(config Config) eventQueue {
+	if config.QueueType == QueueRing {
+		capacity := config.MaxQueueSize
+		if capacity <= 0 {
+			capacity = config.BatchSize
+		}
+		return &ringQueue{buf: make([]Event, capacity), max: config.MaxQueueSize}
+	}
+	return &sliceQueue{events: make([]Event, 0, config.BatchSize), batchSize: config.BatchSize, max: config.MaxQueueSize}
+}
+
+// sliceQueue is the default eventQueue.
+type sliceQueue struct {
+	events    []Event
+	batchSize int
+	max       int
+}
+
+func (q *sliceQueue) len() int { return len(q.events) }
+
+func (q *sliceQueue) push(events ...Event) []Event {
+	q.events = append(q.events, events...)
+	return q.evict()
+}
+
+func (q *sliceQueue) pushFront(events ...Event) []Event {
+	q.events = append(append(make([]Event, 0, len(events)+len(q.events)), events...), q.events...)
+	return q.evict()
+}
+
+func (q *sliceQueue) pop(n int) []Event {
+	if n < 0 || n >= len(q.events) {
+		events := q.events
+		q.events = make([]Event, 0, q.batchSize)
+		return events
+	}
+	events := make([]Event, n)
+	copy(events, q.events[:n])
+	q.events = append(q.events[:0], q.events[n:]...)
+	return events
+}
+
+// evict drops the oldest events beyond max.
+func (q *sliceQueue) evict() []Event {
+	if q.max <= 0 || len(q.events) <= q.max {
+		return nil
+	}
+	over := len(q.events) - q.max
+	evicted := make([]Event, over)
+	copy(evicted, q.events[:over])
+	q.events = append(q.events[:0], q.events[over:]...)
+	return evicted
+}
+
+// ringQueue is a circular buffer of events. When unbounded it doubles its
+// storage as needed; when bounded by max it overwrites the oldest events.
+type ringQueue struct {
+	buf   []Event
+	head  int
+	count int
+	max   int
+}
+
+func (q *ringQueue) len() int { return q.count }
+
+func (q *ringQueue) push(events ...Event) []Event {
+	var evicted []Event
+	for _, event := range events {
+		if q.count == len(q.buf) {
+			if q.max > 0 {
+				evicted = append(evicted, q.buf[q.head])
+				q.buf[q.head] = Event{}
+				q.head = (q.head + 1) % len(q.buf)
+				q.count--
+			} else {
+				q.grow()
+			}
+		}
+		q.buf[(q.head+q.count)%len(q.buf)] = event
+		q.count++
+	}
+	return evicted
+}
+
+func (q *ringQueue) pushFront(events ...Event) []Event {
+	var evicted []Event
+	for i := len(events) - 1; i >= 0; i-- {
+		if q.count == len(q.buf) {
+			if q.max > 0 {
+				// The events being inserted are the oldest; drop the rest.
+				return append(evicted, events[:i+1]...)
+			}
+			q.grow()
+		}
+		q.head = (q.head - 1 + len(q.buf)) % len(q.buf)
+		q.buf[q.head] = events[i]
+		q.count++
+	}
+	return evicted
+}
+
+func (q *ringQueue) pop(n int) []Event {
+	if n < 0 || n > q.count {
+		n = q.count
+	}
+	events := make([]Event, n)
+	for i := range events {
+		idx := (q.head + i) % len(q.buf)
+		events[i] = q.buf[idx]
+		q.buf[idx] = Event{}
+	}
+	q.head = (q.head + n) % len(q.buf)
+	q.count -= n
+	return events
+}
+
+// grow doubles the buffer, unrolling the ring so head is at index 0.
+func (q *ringQueue) grow() {
+	size := len(q.buf) * 2
+	if size == 0 {
+		size = 1
+	}
+	buf := make([]Event, size)
+	for i := 0; i < q.count; i++ {
+		buf[i] = q.buf[(q.head+i)%len(q.buf)]
+	}
+	q.buf = buf
+	q.head = 0
+}
+
+// The helpers below are the only code that mutates c.queue, keeping the
+// event count and byte size used by the flush thresholds consistent. All of
+// them require c.mu to be held. Evicted events are returned so the caller
+// can report them with reportEvicted once c.mu is released.
+
+// pushLocked appends events to the back of the queue.
+func (c *Client) pushLocked(events ...Event) []Event {
+	for _, event := range events {
+		c.queueBytes += event.size
+	}
+	return c.evictedLocked(c.queue.push(events...))
+}
+
+// pushFrontLocked inserts events at the front of the queue, preserving their
+// order.
+func (c *Client) pushFrontLocked(events ...Event) []Event {
+	for _, event := range events {
+		c.queueBytes += event.size
+	}
+	return c.evictedLocked(c.queue.pushFront(events...))
+}
+
+// popLocked removes and returns up to n events from the front of the queue.
+// A negative n removes everything.
+func (c *Client) popLocked(n int) []Event {
+	events := c.queue.pop(n)
+	for _, event := range events {
+		c.queueBytes -= event.size
+	}
+	return events
+}
+
+func (c *Client) evictedLocked(evicted []Event) []Event {
+	for _, event := range evicted {
+		c.queueBytes -= event.size
+	}
+	return evicted
+}
+
+// reportEvicted diverts events evicted from a full queue to the overflow
+// sink, or reports them as dropped. It must be called without c.mu held,
+// since the sink and OnDrop may capture new events.
+func (c *Client) reportEvicted(evicted []Event) {
+	if len(evicted) == 0 || c.spill(evicted) {
+		return
+	}
+	if c.config.Debug {
+		fmt.Printf("[PulseKit] Queue full, dropped %d oldest event(s)\n", len(evicted))
+	}
+	for _, event := range evicted {
+		c.drop(event, DropReasonQueueFull)
+	}
+}
+
+// shouldFlushLocked evaluates every size-based flush trigger at once:
+// BatchSize (queued events plus check-ins) and FlushBytes. FlushInterval is
+// handled by the flush loop. Because the check and the queue swap in Flush
+// both happen under c.mu, concurrent triggers never send the same event
+// twice; a second flush simply finds the queue empty.
+func (c *Client) shouldFlushLocked() bool {
+	if c.queue.len()+len(c.checkIns) >= c.config.BatchSize {
+		return true
+	}
+	return c.config.FlushBytes > 0 && c.queueBytes >= c.config.FlushBytes
+}
+
+// encodedSize returns the serialized size of event, used for FlushBytes.
+func (c *Client) encodedSize(event Event) int {
+	var w countingWriter
+	if _, err := c.config.Serializer.Serialize(&w, event); err != nil {
+		return 0
+	}
+	return int(w)
+}
+
+// countingWriter discards its input, counting the bytes written.
+type countingWriter int
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
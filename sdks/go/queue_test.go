@@ -0,0 +1,181 @@
+package pulsekit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or a second has passed.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// sizedEvent returns a "log" event whose encoded size is the same for every
+// i below 10.
+func sizedEvent(i int) Event {
+	return Event{Type: "log", Message: fmt.Sprintf("message %d", i)}
+}
+
+// eventSize returns the encoded size of a sizedEvent as queued by c.
+func eventSize(c *Client) int {
+	event := sizedEvent(0)
+	c.prepare(&event)
+	return c.encodedSize(event)
+}
+
+func TestFlushTriggers(t *testing.T) {
+	const never = time.Hour
+
+	tests := []struct {
+		name string
+		// config is applied on top of a FakeClock and a FlushInterval of
+		// an hour; flushBytes, if set, is multiplied by the event size.
+		config     Config
+		flushBytes int
+		// flushAfter is the number of captures that triggers the first
+		// flush, or 0 if only FlushInterval should flush.
+		flushAfter int
+		interval   time.Duration
+	}{
+		{name: "BatchSize", config: Config{BatchSize: 3}, flushAfter: 3},
+		{name: "FlushBytes", config: Config{BatchSize: 100}, flushBytes: 2, flushAfter: 2},
+		{name: "FlushInterval", config: Config{BatchSize: 100, FlushInterval: time.Second}, interval: time.Second},
+		{name: "BatchSize before FlushBytes", config: Config{BatchSize: 2}, flushBytes: 5, flushAfter: 2},
+		{name: "FlushBytes before BatchSize", config: Config{BatchSize: 5}, flushBytes: 3, flushAfter: 3},
+		{name: "FlushInterval before BatchSize and FlushBytes", config: Config{BatchSize: 5, FlushInterval: time.Second}, flushBytes: 5, interval: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			clock := NewFakeClock(time.Unix(0, 0))
+			config := tt.config
+			config.Clock = clock
+			if config.FlushInterval == 0 {
+				config.FlushInterval = never
+			}
+			c := newTestClient(t, ts, config)
+			if tt.flushBytes > 0 {
+				c.config.FlushBytes = tt.flushBytes * eventSize(c)
+			}
+
+			if tt.flushAfter > 0 {
+				for i := 0; i < tt.flushAfter-1; i++ {
+					c.Capture(sizedEvent(i))
+				}
+				if got := len(ts.received()); got != 0 {
+					t.Fatalf("flushed %d events before the trigger", got)
+				}
+				c.Capture(sizedEvent(tt.flushAfter - 1))
+				if got := len(ts.received()); got != tt.flushAfter {
+					t.Fatalf("received %d events after the trigger, want %d", got, tt.flushAfter)
+				}
+				return
+			}
+
+			waitFor(t, "the flush loop's ticker", func() bool {
+				clock.mu.Lock()
+				defer clock.mu.Unlock()
+				return len(clock.tickers) > 0
+			})
+			c.Capture(sizedEvent(0))
+			c.Capture(sizedEvent(1))
+			clock.Advance(tt.interval - time.Millisecond)
+			time.Sleep(10 * time.Millisecond)
+			if got := len(ts.received()); got != 0 {
+				t.Fatalf("flushed %d events before FlushInterval elapsed", got)
+			}
+			clock.Advance(time.Millisecond)
+			waitFor(t, "the interval flush", func() bool { return len(ts.received()) == 2 })
+		})
+	}
+}
+func TestRingQueueWraparound(t *testing.T) {
+	q := &ringQueue{buf: make([]Event, 4)}
+	q.push(sizedEvent(0), sizedEvent(1), sizedEvent(2))
+	q.pop(2)
+	q.push(sizedEvent(3), sizedEvent(4), sizedEvent(5)) // wraps around the end of buf
+
+	if len(q.buf) != 4 {
+		t.Fatalf("buffer grew to %d while wrapping", len(q.buf))
+	}
+	assertMessages(t, q.pop(-1), "message 2", "message 3", "message 4", "message 5")
+}
+
+func TestRingQueueGrowth(t *testing.T) {
+	q := &ringQueue{buf: make([]Event, 2)}
+	q.push(sizedEvent(0), sizedEvent(1))
+	q.pop(1)
+	q.push(sizedEvent(2), sizedEvent(3), sizedEvent(4))
+
+	if q.len() != 4 || len(q.buf) < 4 {
+		t.Fatalf("len = %d, cap = %d after growing", q.len(), len(q.buf))
+	}
+	assertMessages(t, q.pop(-1), "message 1", "message 2", "message 3", "message 4")
+}
+
+func TestQueueMaxSizeEvictsOldest(t *testing.T) {
+	for _, queueType := range []QueueType{QueueSlice, QueueRing} {
+		t.Run(string(queueType), func(t *testing.T) {
+			q := newEventQueue(Config{QueueType: queueType, MaxQueueSize: 3, BatchSize: 10})
+			q.push(sizedEvent(0), sizedEvent(1), sizedEvent(2))
+			evicted := q.push(sizedEvent(3), sizedEvent(4))
+
+			assertMessages(t, evicted, "message 0", "message 1")
+			assertMessages(t, q.pop(-1), "message 2", "message 3", "message 4")
+		})
+	}
+}
+
+func TestQueuePushFrontWhenFull(t *testing.T) {
+	for _, queueType := range []QueueType{QueueSlice, QueueRing} {
+		t.Run(string(queueType), func(t *testing.T) {
+			q := newEventQueue(Config{QueueType: queueType, MaxQueueSize: 3, BatchSize: 10})
+			q.push(sizedEvent(2), sizedEvent(3))
+			// Re-queued events are older than the queued ones, so they are
+			// the ones evicted once the queue is full.
+			evicted := q.pushFront(sizedEvent(0), sizedEvent(1))
+
+			assertMessages(t, evicted, "message 0")
+			assertMessages(t, q.pop(-1), "message 1", "message 2", "message 3")
+		})
+	}
+}
+
+func assertMessages(t *testing.T, events []Event, want ...string) {
+	t.Helper()
+	got := make([]string, len(events))
+	for i, event := range events {
+		got[i] = event.Message
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+}
+
+// benchmarkQueue simulates sustained load: events are pushed one at a time
+// and drained a batch at a time, as by the flush loop.
+func benchmarkQueue(b *testing.B, queueType QueueType) {
+	const batchSize = 100
+	q := newEventQueue(Config{QueueType: queueType, BatchSize: batchSize})
+	event := sizedEvent(0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		q.push(event)
+		if q.len() == batchSize {
+			q.pop(-1)
+		}
+	}
+}
+
+func BenchmarkQueueSlice(b *testing.B) { benchmarkQueue(b, QueueSlice) }
+
+func BenchmarkQueueRing(b *testing.B) { benchmarkQueue(b, QueueRing) }
@@ -0,0 +1,204 @@
+package pulsekit
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const defaultMaxRateLimitKeys = 1000
+
+// rateLimiter is a token bucket that admits up to rate events per second
+// with a burst of the same size. A fraction of the bucket can be reserved
+// for error and fatal events so that floods of lower-severity events cannot
+// starve them.
+type rateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	reserved float64
+	tokens   float64
+	last     time.Time
+}
+
+func newRateLimiter(rate, reservedFraction float64, now time.Time) *rateLimiter {
+	capacity := rate
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &rateLimiter{
+		rate:     rate,
+		capacity: capacity,
+		reserved: capacity * reservedFraction,
+		tokens:   capacity,
+		last:     now,
+	}
+}
+
+// allow reports whether an event at level may be sent at time now, and
+// whether it consumed reserved budget.
+func (rl *rateLimiter) allow(level Level, now time.Time) (ok, usedReserve bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.advanceLocked(now)
+	if rl.tokens < 1 {
+		return false, false
+	}
+	remaining := rl.tokens - 1
+	if remaining < rl.reserved {
+		if !level.atLeast(LevelError) {
+			return false, false
+		}
+		usedReserve = true
+	}
+	rl.tokens = remaining
+	return true, usedReserve
+}
+
+// available returns the number of tokens in the bucket at time now.
+func (rl *rateLimiter) available(now time.Time) float64 {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.advanceLocked(now)
+	return rl.tokens
+}
+
+// advanceLocked adds the tokens accrued since the last update.
+func (rl *rateLimiter) advanceLocked(now time.Time) {
+	if elapsed := now.Sub(rl.last).Seconds(); elapsed > 0 {
+		rl.tokens += elapsed * rl.rate
+		if rl.tokens > rl.capacity {
+			rl.tokens = rl.capacity
+		}
+		rl.last = now
+	}
+}
+
+// refill restores a full bucket as of now.
+func (rl *rateLimiter) refill(now time.Time) {
+	rl.mu.Lock()
+	rl.tokens = rl.capacity
+	rl.last = now
+	rl.mu.Unlock()
+}
+
+// keyedRateLimiter keeps a token bucket per rate-limit key, evicting the
+// least recently used bucket once maxKeys are tracked.
+type keyedRateLimiter struct {
+	mu       sync.Mutex
+	rate     float64
+	reserved float64
+	maxKeys  int
+	order    *list.List
+	buckets  map[string]*list.Element
+}
+
+type keyedBucket struct {
+	key     string
+	limiter *rateLimiter
+	// limited counts the events this bucket rejected.
+	limited int64
+}
+
+func newKeyedRateLimiter(rate, reservedFraction float64, maxKeys int) *keyedRateLimiter {
+	return &keyedRateLimiter{
+		rate:     rate,
+		reserved: reservedFraction,
+		maxKeys:  maxKeys,
+		order:    list.New(),
+		buckets:  make(map[string]*list.Element),
+	}
+}
+
+// allow is rateLimiter.allow for key's bucket, creating it full if needed.
+func (kl *keyedRateLimiter) allow(key string, level Level, now time.Time) (ok, usedReserve bool) {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	el, found := kl.buckets[key]
+	if found {
+		kl.order.MoveToFront(el)
+	} else {
+		el = kl.order.PushFront(&keyedBucket{key: key, limiter: newRateLimiter(kl.rate, kl.reserved, now)})
+		kl.buckets[key] = el
+		for kl.order.Len() > kl.maxKeys {
+			back := kl.order.Back()
+			kl.order.Remove(back)
+			delete(kl.buckets, back.Value.(*keyedBucket).key)
+		}
+	}
+	bucket := el.Value.(*keyedBucket)
+	ok, usedReserve = bucket.limiter.allow(level, now)
+	if !ok {
+		bucket.limited++
+	}
+	return ok, usedReserve
+}
+
+// limited returns the rejection counts of the tracked keys that have
+// rejected at least one event.
+func (kl *keyedRateLimiter) limited() map[string]int64 {
+	kl.mu.Lock()
+	defer kl.mu.Unlock()
+
+	var counts map[string]int64
+	for el := kl.order.Front(); el != nil; el = el.Next() {
+		bucket := el.Value.(*keyedBucket)
+		if bucket.limited == 0 {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int64)
+		}
+		counts[bucket.key] = bucket.limited
+	}
+	return counts
+}
+
+// clear forgets every bucket.
+func (kl *keyedRateLimiter) clear() {
+	kl.mu.Lock()
+	kl.order.Init()
+	kl.buckets = make(map[string]*list.Element)
+	kl.mu.Unlock()
+}
+
+// WithRateLimitKey subjects the event to the Config.PerKeyEventsPerSecond
+// bucket for key, such as a tenant or endpoint, in addition to the global
+// EventsPerSecond limit.
+func WithRateLimitKey(key string) EventOption {
+	return func(e *Event) {
+		e.rateLimitKey = key
+	}
+}
+
+// rateLimited applies Config.PerKeyEventsPerSecond and then
+// Config.EventsPerSecond, recording the outcome in Stats and reporting
+// dropped events.
+func (c *Client) rateLimited(event Event) bool {
+	if c.keyLimiter != nil && event.rateLimitKey != "" {
+		ok, usedReserve := c.keyLimiter.allow(event.rateLimitKey, event.Level, c.config.Clock.Now())
+		if usedReserve {
+			c.stats.add(func(s *Stats) { s.ReservedBudgetUsed++ })
+		}
+		if !ok {
+			c.stats.add(func(s *Stats) { s.KeyRateLimited++ })
+			c.drop(event, DropReasonRateLimited)
+			return true
+		}
+	}
+	if c.limiter == nil {
+		return false
+	}
+	ok, usedReserve := c.limiter.allow(event.Level, c.config.Clock.Now())
+	if usedReserve {
+		c.stats.add(func(s *Stats) { s.ReservedBudgetUsed++ })
+	}
+	if !ok {
+		c.stats.add(func(s *Stats) { s.RateLimited++ })
+		c.drop(event, DropReasonRateLimited)
+		return true
+	}
+	return false
+}
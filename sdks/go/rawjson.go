@@ -0,0 +1,36 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithMetadataJSON adds a pre-serialized JSON value to the event's metadata.
+// It is embedded in the request as is, avoiding a second marshal of large
+// payloads. Malformed JSON is removed before the event is queued so it
+// cannot corrupt a batch.
+func WithMetadataJSON(key string, raw json.RawMessage) EventOption {
+	return WithMetadata(map[string]interface{}{key: raw})
+}
+
+// validateRawMetadata removes json.RawMessage metadata values that are not
+// well-formed JSON. The caller's map is left untouched.
+func (c *Client) validateRawMetadata(event *Event) {
+	var metadata map[string]interface{}
+	for k, v := range event.Metadata {
+		raw, ok := v.(json.RawMessage)
+		if !ok || json.Valid(raw) {
+			continue
+		}
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Dropping malformed JSON metadata %q\n", k)
+		}
+		if metadata == nil {
+			metadata = copyMap(event.Metadata)
+		}
+		delete(metadata, k)
+	}
+	if metadata != nil {
+		event.Metadata = metadata
+	}
+}
@@ -0,0 +1,105 @@
+// Package redisdedupe provides a pulsekit.DedupeStore backed by Redis, so a
+// fleet of instances suppresses duplicate events globally rather than per
+// process.
+//
+// The package does not import a Redis client. Adapt your client to the
+// SetNXer interface; with github.com/redis/go-redis/v9:
+//
+//	type goRedis struct{ *redis.Client }
+//
+//	func (r goRedis) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+//		return r.Client.SetNX(ctx, key, 1, ttl).Result()
+//	}
+//
+//	func (r goRedis) Del(ctx context.Context, key string) error {
+//		return r.Client.Del(ctx, key).Err()
+//	}
+//
+//	store := redisdedupe.New(goRedis{rdb})
+//
+// Consistency tradeoffs: the check-and-record is a single SET NX, so
+// concurrent instances agree on which one sends. Keys expire with the dedupe
+// window, so suppression is bounded by Redis' expiry precision. Each capture
+// of a dedupe-eligible event makes a round trip to Redis on the capturing
+// goroutine, bounded by Timeout. If Redis is slow or unavailable the store
+// fails open: events are treated as unseen and sent, so an outage of Redis
+// produces duplicates rather than lost events.
+package redisdedupe
+
+import (
+	"context"
+	"time"
+)
+
+// defaultTimeout bounds each Redis call when Store.Timeout is unset.
+const defaultTimeout = 50 * time.Millisecond
+
+// SetNXer sets key with a TTL only if it does not exist, reporting whether
+// it was set.
+type SetNXer interface {
+	SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// Deleter deletes key. Clients implementing it in addition to SetNXer let
+// the Store implement pulsekit.DedupeForgetter.
+type Deleter interface {
+	Del(ctx context.Context, key string) error
+}
+
+// Store is a pulsekit.DedupeStore backed by Redis.
+type Store struct {
+	client SetNXer
+	// Prefix is prepended to fingerprints to form Redis keys
+	// (default: "pulsekit:dedupe:")
+	Prefix string
+	// Timeout bounds each Redis call (default: 50ms)
+	Timeout time.Duration
+	// OnError, if set, is called when a Redis call fails
+	OnError func(error)
+}
+
+// New returns a Store using client.
+func New(client SetNXer) *Store {
+	return &Store{
+		client:  client,
+		Prefix:  "pulsekit:dedupe:",
+		Timeout: defaultTimeout,
+	}
+}
+
+// Seen implements pulsekit.DedupeStore. It reports true when another
+// instance (or this one) recorded fingerprint within window.
+func (s *Store) Seen(fingerprint string, window time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+
+	set, err := s.client.SetNX(ctx, s.Prefix+fingerprint, window)
+	if err != nil {
+		if s.OnError != nil {
+			s.OnError(err)
+		}
+		return false
+	}
+	return !set
+}
+
+// Forget implements pulsekit.DedupeForgetter when the client implements
+// Deleter; otherwise the record simply expires with the dedupe window.
+func (s *Store) Forget(fingerprint string) {
+	deleter, ok := s.client.(Deleter)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+	if err := deleter.Del(ctx, s.Prefix+fingerprint); err != nil && s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+func (s *Store) timeout() time.Duration {
+	if s.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return s.Timeout
+}
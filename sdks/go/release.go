@@ -0,0 +1,86 @@
+package pulsekit
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// shortCommitLen is the number of revision characters used in detected
+// releases.
+const shortCommitLen = 7
+
+// DetectRelease derives a release identifier from the build information
+// embedded by the Go toolchain. It returns the short VCS revision, suffixed
+// with "+dirty" when the working tree was modified, falling back to the main
+// module version. It returns "" when neither is available.
+func DetectRelease() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+
+	if revision != "" {
+		if len(revision) > shortCommitLen {
+			revision = revision[:shortCommitLen]
+		}
+		if modified {
+			revision += "+dirty"
+		}
+		return revision
+	}
+
+	if version := info.Main.Version; version != "" && version != "(devel)" {
+		return version
+	}
+	return ""
+}
+
+// collectDeps returns the versions of the build's dependencies matching
+// allowlist, keyed by module path. An allowlist entry matches the module
+// itself and any module below it (e.g. "github.com/aws/aws-sdk-go-v2"
+// matches ".../service/s3"). Replaced modules report the replacement's
+// version, or its path for local replacements.
+func collectDeps(allowlist []string) map[string]string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	deps := make(map[string]string)
+	for _, dep := range info.Deps {
+		if !depAllowed(dep.Path, allowlist) {
+			continue
+		}
+		version := dep.Version
+		if r := dep.Replace; r != nil {
+			version = r.Version
+			if version == "" {
+				version = r.Path
+			}
+		}
+		deps[dep.Path] = version
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	return deps
+}
+
+func depAllowed(path string, allowlist []string) bool {
+	for _, prefix := range allowlist {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package pulsekit
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultSanitizeQueryParams are the query parameters redacted from captured
+// request URLs when Config.SanitizeQueryParams is unset.
+var defaultSanitizeQueryParams = []string{
+	"token", "access_token", "refresh_token", "key", "api_key", "apikey",
+	"password", "secret", "signature", "sig",
+}
+
+// RequestInfo is the request context attached by WithRequest.
+type RequestInfo struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// WithRequest attaches the request's method and URL to the event as the
+// "request" context. The URL is sanitized according to
+// Config.SanitizeQueryParams and Config.StripQueryString before the event is
+// queued.
+func WithRequest(r *http.Request) EventOption {
+	return func(e *Event) {
+		e.request = r
+	}
+}
+
+// applyRequest resolves a pending WithRequest into the "request" context.
+func (c *Client) applyRequest(event *Event) {
+	if event.request == nil {
+		return
+	}
+	r := event.request
+	event.request = nil
+	event.setContext("request", RequestInfo{
+		Method: r.Method,
+		URL:    c.sanitizeURL(requestURL(r)),
+	})
+	applyResponse(r.Context(), event)
+}
+
+// requestURL returns r's URL, filling in the scheme and host for server
+// requests whose URL only carries the path and query.
+func requestURL(r *http.Request) *url.URL {
+	u := *r.URL
+	if u.Host == "" {
+		u.Host = r.Host
+	}
+	if u.Scheme == "" && u.Host != "" {
+		u.Scheme = "http"
+		if r.TLS != nil {
+			u.Scheme = "https"
+		}
+	}
+	return &u
+}
+
+// sanitizeURL returns u as a string with the password and sensitive query
+// parameter values redacted, or the query removed entirely when
+// Config.StripQueryString is set. Parameter order is preserved.
+func (c *Client) sanitizeURL(u *url.URL) string {
+	clean := *u
+	clean.Fragment = ""
+	clean.RawFragment = ""
+	if c.config.StripQueryString {
+		clean.RawQuery = ""
+		clean.ForceQuery = false
+	} else if clean.RawQuery != "" {
+		pairs := strings.Split(clean.RawQuery, "&")
+		for i, pair := range pairs {
+			rawName, _, _ := strings.Cut(pair, "=")
+			name, err := url.QueryUnescape(rawName)
+			if err != nil {
+				name = rawName
+			}
+			if c.sensitiveQueryParam(name) {
+				pairs[i] = rawName + "=" + redacted
+			}
+		}
+		clean.RawQuery = strings.Join(pairs, "&")
+	}
+	return clean.Redacted()
+}
+
+func (c *Client) sensitiveQueryParam(name string) bool {
+	for _, param := range c.config.SanitizeQueryParams {
+		if strings.EqualFold(name, param) {
+			return true
+		}
+	}
+	return false
+}
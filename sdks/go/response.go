@@ -0,0 +1,79 @@
+package pulsekit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ResponseInfo is the response context attached to error and fatal events
+// captured while Middleware is serving a request.
+type ResponseInfo struct {
+	Status       int   `json:"status"`
+	BytesWritten int64 `json:"bytes_written"`
+}
+
+// responseRecorder wraps an http.ResponseWriter to record the status code
+// and body size sent to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher when the wrapped writer does.
+func (w *responseRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the wrapped writer does.
+func (w *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer.
+func (w *responseRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseRecorder) info() ResponseInfo {
+	return ResponseInfo{Status: w.status, BytesWritten: w.bytes}
+}
+
+// applyResponse attaches the response recorded for the request carrying ctx
+// to error and fatal events.
+func applyResponse(ctx context.Context, event *Event) {
+	if ctx == nil || !event.Level.atLeast(LevelError) {
+		return
+	}
+	if w, ok := ctx.Value(responseContextKey).(*responseRecorder); ok {
+		event.setContext("response", w.info())
+	}
+}
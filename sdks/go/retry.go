@@ -0,0 +1,83 @@
+package pulsekit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// retryBaseDelay is the backoff before the first retry; it doubles on each
+// subsequent attempt.
+const retryBaseDelay = 500 * time.Millisecond
+
+// WithMaxRetries overrides Config.MaxRetries for this event. It applies when
+// the event is sent on its own, such as a synchronous send via SyncAbove or
+// SynchronousMode. Negative values are treated as 0.
+func WithMaxRetries(n int) EventOption {
+	if n < 0 {
+		n = 0
+	}
+	return func(e *Event) {
+		retries := n
+		e.maxRetries = &retries
+	}
+}
+
+// maxRetriesFor returns the retry limit for a request carrying events.
+func (c *Client) maxRetriesFor(events []Event) int {
+	if len(events) == 1 && events[0].maxRetries != nil {
+		return *events[0].maxRetries
+	}
+	return c.config.MaxRetries
+}
+
+// retryable reports whether a failed send may succeed if repeated: network
+// errors, 429 Too Many Requests and 5xx responses.
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.code == http.StatusTooManyRequests || se.code >= 500
+	}
+	var ee *encodeError
+	return !errors.As(err, &ee)
+}
+
+// withRetries calls send until it succeeds, fails with a non-retryable error,
+// or maxRetries retries have been made, backing off exponentially between
+// attempts. It returns the last error.
+func (c *Client) withRetries(ctx context.Context, maxRetries int, send func() error) error {
+	delay := retryBaseDelay
+	for attempt := 0; ; attempt++ {
+		err := send()
+		if attempt >= maxRetries || !retryable(err) || !c.retryAllowed() {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// retryAllowed draws a token from the retry budget set by
+// Config.RetryBudgetPerSecond. When the budget is exhausted, failed sends
+// are not retried, so retries cannot amplify an outage.
+func (c *Client) retryAllowed() bool {
+	if c.retryBudget == nil {
+		return true
+	}
+	if ok, _ := c.retryBudget.allow(LevelFatal, c.config.Clock.Now()); ok {
+		return true
+	}
+	c.stats.add(func(s *Stats) { s.RetryBudgetExhausted++ })
+	return false
+}
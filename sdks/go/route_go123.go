@@ -0,0 +1,18 @@
+//go:build go1.23
+
+package pulsekit
+
+import (
+	"net/http"
+	"strings"
+)
+
+// serveMuxPattern returns the http.ServeMux pattern that matched r, without
+// its method prefix (e.g., "/users/{id}" for "GET /users/{id}").
+func serveMuxPattern(r *http.Request) string {
+	pattern := r.Pattern
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = pattern[i+1:]
+	}
+	return pattern
+}
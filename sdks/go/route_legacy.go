@@ -0,0 +1,11 @@
+//go:build !go1.23
+
+package pulsekit
+
+import "net/http"
+
+// serveMuxPattern is unavailable before Go 1.23, which added
+// http.Request.Pattern.
+func serveMuxPattern(r *http.Request) string {
+	return ""
+}
@@ -0,0 +1,40 @@
+package pulsekit
+
+import "math/rand"
+
+// samplingInfo is recorded under the "_sampling" metadata key when
+// Config.AnnotateSampling is set.
+type samplingInfo struct {
+	// Rate is the probability with which the event was kept.
+	Rate float64 `json:"rate"`
+	// Forced is true when the event bypassed SampleRate.
+	Forced bool `json:"forced"`
+	// Reason is "rate", "trace" or "force" (events the SDK always sends,
+	// such as the ReportStartup event).
+	Reason string `json:"reason"`
+}
+
+// sampledOut reports whether event is discarded by sampling. With
+// Config.TraceSampling, events carrying a trace context follow the trace's
+// sampled flag instead of SampleRate. Force-sent events are always kept.
+func (c *Client) sampledOut(event *Event) bool {
+	keep := true
+	info := samplingInfo{Rate: c.config.SampleRate, Reason: "rate"}
+	if event.forceSend {
+		info = samplingInfo{Rate: 1, Forced: true, Reason: "force"}
+	} else if c.config.TraceSampling && event.Trace != nil {
+		keep = event.Trace.Sampled
+		info = samplingInfo{Rate: 1, Forced: true, Reason: "trace"}
+	} else if c.config.SampleRate < 1 {
+		keep = rand.Float64() < c.config.SampleRate
+	}
+	if !keep {
+		c.drop(*event, DropReasonSampled)
+		return true
+	}
+	if c.config.AnnotateSampling {
+		event.Metadata = copyMap(event.Metadata)
+		event.Metadata["_sampling"] = info
+	}
+	return false
+}
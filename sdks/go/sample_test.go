@@ -0,0 +1,23 @@
+package pulsekit
+
+import "testing"
+
+func TestStartupEventBypassesSampling(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts, Config{
+		ReportStartup:    true,
+		SampleRate:       0.000001,
+		EventsPerSecond:  0.000001,
+		AnnotateSampling: true,
+	})
+	c.Flush()
+
+	events := ts.received()
+	if len(events) != 1 || events[0].Type != "pulsekit.sdk.started" {
+		t.Fatalf("received %+v, want the startup event", events)
+	}
+	sampling, _ := events[0].Metadata["_sampling"].(map[string]interface{})
+	if sampling["forced"] != true || sampling["reason"] != "force" {
+		t.Errorf("_sampling = %v, want forced with reason \"force\"", sampling)
+	}
+}
@@ -0,0 +1,166 @@
+package pulsekit
+
+import (
+	"math"
+	"regexp"
+)
+
+const (
+	filtered = "[FILTERED]"
+
+	// minSecretLen is the length of the shortest secret the patterns match;
+	// shorter strings are not scanned at all.
+	minSecretLen = 20
+	// minEntropyLen and minEntropy bound the generic high-entropy check: a
+	// run of at least minEntropyLen token characters averaging at least
+	// minEntropy bits per character. Hex digests such as commit hashes, and
+	// most identifiers, stay below the threshold; URL paths are checked one
+	// segment at a time.
+	minEntropyLen = 32
+	minEntropy    = 4.5
+)
+
+// secretPattern matches well-known credential formats: AWS access key IDs,
+// GitHub tokens, JWTs and PEM private keys (to the end of the string if the
+// END line is missing).
+var secretPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b` +
+	`|\bgh[pousr]_[A-Za-z0-9]{36,}\b` +
+	`|\bgithub_pat_[A-Za-z0-9_]{22,}\b` +
+	`|\beyJ[A-Za-z0-9_-]{4,}\.eyJ[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]{8,}` +
+	`|-----BEGIN [A-Z ]*PRIVATE KEY-----(?s:.*?)(?:-----END [A-Z ]*PRIVATE KEY-----|$)`)
+
+// filterSecrets replaces secrets detected in the message and metadata string
+// values when Config.DetectSecrets is set. Maps and slices are copied only
+// where something is replaced, so the caller's metadata is never modified.
+func (c *Client) filterSecrets(event *Event) {
+	if !c.config.DetectSecrets {
+		return
+	}
+	event.Message = filterSecretString(event.Message)
+	if m, ok := filterSecretValue(event.Metadata); ok {
+		event.Metadata = m.(map[string]interface{})
+	}
+}
+
+// filterSecretValue returns v with secrets in its strings replaced, and
+// whether anything changed. Only strings and the plain maps and slices
+// metadata is usually built from are scanned.
+func filterSecretValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		out := filterSecretString(v)
+		return out, out != v
+	case []string:
+		var out []string
+		for i, s := range v {
+			if f := filterSecretString(s); f != s {
+				if out == nil {
+					out = append([]string(nil), v...)
+				}
+				out[i] = f
+			}
+		}
+		return out, out != nil
+	case []interface{}:
+		var out []interface{}
+		for i, elem := range v {
+			if f, ok := filterSecretValue(elem); ok {
+				if out == nil {
+					out = append([]interface{}(nil), v...)
+				}
+				out[i] = f
+			}
+		}
+		return out, out != nil
+	case map[string]interface{}:
+		var out map[string]interface{}
+		for k, elem := range v {
+			if f, ok := filterSecretValue(elem); ok {
+				if out == nil {
+					out = copyMap(v)
+				}
+				out[k] = f
+			}
+		}
+		return out, out != nil
+	case map[string]string:
+		var out map[string]string
+		for k, s := range v {
+			if f := filterSecretString(s); f != s {
+				if out == nil {
+					out = copyMap(v)
+				}
+				out[k] = f
+			}
+		}
+		return out, out != nil
+	}
+	return v, false
+}
+
+// filterSecretString replaces the secrets found in s.
+func filterSecretString(s string) string {
+	if len(s) < minSecretLen {
+		return s
+	}
+	s = secretPattern.ReplaceAllLiteralString(s, filtered)
+	if len(s) < minEntropyLen {
+		return s
+	}
+
+	var out []byte
+	last := 0
+	for i := 0; i < len(s); {
+		if !isTokenChar(s[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(s) && isTokenChar(s[j]) {
+			j++
+		}
+		if j-i >= minEntropyLen && highEntropy(s[i:j]) {
+			out = append(append(out, s[last:i]...), filtered...)
+			last = j
+		}
+		i = j
+	}
+	if out == nil {
+		return s
+	}
+	return string(append(out, s[last:]...))
+}
+
+// isTokenChar reports whether b can appear in a base64url or hex encoded
+// token. '/' is left out even though standard base64 uses it, so that a
+// URL path is not scanned as a single token.
+func isTokenChar(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' ||
+		b == '+' || b == '=' || b == '_' || b == '-'
+}
+
+// highEntropy reports whether token mixes upper and lower case letters and
+// digits and has a Shannon entropy of at least minEntropy bits per character.
+func highEntropy(token string) bool {
+	var counts [256]int
+	var upper, lower, digits bool
+	for i := 0; i < len(token); i++ {
+		b := token[i]
+		counts[b]++
+		upper = upper || b >= 'A' && b <= 'Z'
+		lower = lower || b >= 'a' && b <= 'z'
+		digits = digits || b >= '0' && b <= '9'
+	}
+	if !upper || !lower || !digits {
+		return false
+	}
+	n := float64(len(token))
+	entropy := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / n
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy >= minEntropy
+}
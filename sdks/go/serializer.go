@@ -0,0 +1,22 @@
+package pulsekit
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Serializer encodes request bodies, allowing encodings other than JSON.
+type Serializer interface {
+	// Serialize writes the encoded form of v to w and returns the content
+	// type to send with it.
+	Serialize(w io.Writer, v interface{}) (contentType string, err error)
+}
+
+// jsonSerializer is the default Serializer.
+type jsonSerializer struct {
+	contentType string
+}
+
+func (s jsonSerializer) Serialize(w io.Writer, v interface{}) (string, error) {
+	return s.contentType, json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,150 @@
+package pulsekit
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// SlogHandlerOptions configures a SlogHandler.
+type SlogHandlerOptions struct {
+	// Level is the minimum record level forwarded (default: slog.LevelInfo)
+	Level slog.Leveler
+}
+
+// SlogHandler is a slog.Handler that forwards log records to PulseKit as
+// "log" events. Attributes become metadata, with groups nested as maps, and
+// the record's call site becomes a single-frame stack trace.
+type SlogHandler struct {
+	client *Client
+	level  slog.Leveler
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewSlogHandler returns a handler forwarding records to client. A nil client
+// forwards to the default client.
+func NewSlogHandler(client *Client, opts *SlogHandlerOptions) *SlogHandler {
+	h := &SlogHandler{client: client, level: slog.LevelInfo}
+	if opts != nil && opts.Level != nil {
+		h.level = opts.Level
+	}
+	return h
+}
+
+// Enabled reports whether records at level are forwarded.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle forwards r as an event.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	client := h.client
+	if client == nil {
+		client = getDefaultClient()
+		if client == nil {
+			return nil
+		}
+	}
+
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	metadata := make(map[string]interface{})
+	addAttrs(metadata, h.attrs)
+	addAttrs(metadata, groupAttrs(h.groups, attrs))
+
+	event := Event{
+		Type:    "log",
+		Level:   slogLevel(r.Level),
+		Message: r.Message,
+		Origin:  OriginSlog,
+	}
+	if len(metadata) > 0 {
+		event.Metadata = metadata
+	}
+	if frame, ok := slogFrame(r.PC); ok {
+		event.Stacktrace = []StackFrame{frame}
+	}
+	client.CaptureNow(ctx, event)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = append(append([]slog.Attr(nil), h.attrs...), groupAttrs(h.groups, attrs)...)
+	return &h2
+}
+
+// WithGroup returns a handler that nests subsequent attributes under name.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(append([]string(nil), h.groups...), name)
+	return &h2
+}
+
+// slogFrame resolves a record's program counter into a stack frame. It
+// returns false for records without a PC.
+func slogFrame(pc uintptr) (StackFrame, bool) {
+	if pc == 0 {
+		return StackFrame{}, false
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.Function == "" && frame.File == "" {
+		return StackFrame{}, false
+	}
+	return StackFrame{File: frame.File, Line: frame.Line, Function: frame.Function}, true
+}
+
+// slogLevel maps a slog level to the closest event level.
+func slogLevel(l slog.Level) Level {
+	switch {
+	case l >= slog.LevelError:
+		return LevelError
+	case l >= slog.LevelWarn:
+		return LevelWarning
+	case l >= slog.LevelInfo:
+		return LevelInfo
+	}
+	return LevelDebug
+}
+
+// groupAttrs nests attrs inside the given groups, outermost first.
+func groupAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	for i := len(groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+	return attrs
+}
+
+// addAttrs adds attrs to m, converting groups into nested maps.
+func addAttrs(m map[string]interface{}, attrs []slog.Attr) {
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		if v.Kind() != slog.KindGroup {
+			if a.Key != "" {
+				m[a.Key] = v.Any()
+			}
+			continue
+		}
+		if a.Key == "" {
+			addAttrs(m, v.Group())
+			continue
+		}
+		sub, ok := m[a.Key].(map[string]interface{})
+		if !ok {
+			sub = make(map[string]interface{})
+			m[a.Key] = sub
+		}
+		addAttrs(sub, v.Group())
+	}
+}
@@ -0,0 +1,42 @@
+package pulsekit
+
+import "context"
+
+// smoothSlices is the number of sub-batches each flush interval is divided
+// into when SmoothDelivery is enabled.
+const smoothSlices = 5
+
+// smoothFlushLoop ticks smoothSlices times per flush interval, sending an
+// even share of the queue each time so that everything queued at the start
+// of an interval is delivered by its end.
+func (c *Client) smoothFlushLoop() {
+	ticker := c.config.Clock.NewTicker(c.config.FlushInterval / smoothSlices)
+	defer ticker.Stop()
+
+	slice := 0
+	for {
+		select {
+		case <-ticker.C():
+			c.flushPortion(smoothSlices - slice)
+			c.flushCheckIns(context.Background())
+			c.sweepCaches()
+			slice = (slice + 1) % smoothSlices
+		case <-c.flushNow:
+			c.Flush()
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// flushPortion sends 1/remaining of the queued events, rounding up so the
+// final slice drains the queue.
+func (c *Client) flushPortion(remaining int) {
+	c.mu.Lock()
+	events := c.popLocked((c.queue.len() + remaining - 1) / remaining)
+	c.mu.Unlock()
+
+	if len(events) > 0 {
+		c.sendEvents(context.Background(), events)
+	}
+}
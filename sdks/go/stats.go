@@ -0,0 +1,117 @@
+package pulsekit
+
+import (
+	"sync"
+	"time"
+)
+
+// ewmaAlpha weights the most recent sample in moving averages.
+const ewmaAlpha = 0.2
+
+// Stats is a snapshot of client delivery metrics.
+type Stats struct {
+	// Flushes is the number of send attempts made
+	Flushes int64
+	// LastFlushDuration is how long the most recent send took
+	LastFlushDuration time.Duration
+	// AvgFlushDuration is an exponentially-weighted moving average of send durations
+	AvgFlushDuration time.Duration
+	// LastBatchSize is the number of events in the most recent send
+	LastBatchSize int
+	// AvgBatchSize is an exponentially-weighted moving average of batch sizes
+	AvgBatchSize float64
+	// RateLimited is the number of events dropped by EventsPerSecond
+	RateLimited int64
+	// KeyRateLimited is the number of events dropped by
+	// PerKeyEventsPerSecond
+	KeyRateLimited int64
+	// RateLimitedByKey counts the events dropped by PerKeyEventsPerSecond
+	// for each rate-limit key still tracked (see MaxRateLimitKeys)
+	RateLimitedByKey map[string]int64
+	// ReservedBudgetUsed is the number of error events admitted using the
+	// budget reserved by ReservedErrorBudgetFraction
+	ReservedBudgetUsed int64
+	// DeadlineExpired is the number of events dropped because they were
+	// still undelivered past their WithDeadline deadline
+	DeadlineExpired int64
+	// Expired is the number of events dropped at flush time for having
+	// been queued longer than MaxDeliveryAge
+	Expired int64
+	// Overflowed is the number of events evicted from a full queue and
+	// handed to Config.OverflowSink
+	Overflowed int64
+	// Failures counts failed sends by category (FailureDNS,
+	// FailureUnauthorized, FailureServerError, ...) after retries
+	Failures map[string]int64
+	// RetryBudgetExhausted is the number of retries skipped because the
+	// RetryBudgetPerSecond budget was exhausted
+	RetryBudgetExhausted int64
+	// RetryBudgetRemaining is the number of retries currently available
+	// from the RetryBudgetPerSecond budget
+	RetryBudgetRemaining float64
+}
+
+// stats accumulates Stats under its own lock so recording never contends
+// with the queue.
+type stats struct {
+	mu sync.Mutex
+	s  Stats
+}
+
+func (st *stats) recordFlush(batchSize int, d time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.s.LastFlushDuration = d
+	st.s.LastBatchSize = batchSize
+	if st.s.Flushes == 0 {
+		st.s.AvgFlushDuration = d
+		st.s.AvgBatchSize = float64(batchSize)
+	} else {
+		st.s.AvgFlushDuration = time.Duration(ewma(float64(st.s.AvgFlushDuration), float64(d)))
+		st.s.AvgBatchSize = ewma(st.s.AvgBatchSize, float64(batchSize))
+	}
+	st.s.Flushes++
+}
+
+// add applies fn to the stats under the lock.
+func (st *stats) add(fn func(*Stats)) {
+	st.mu.Lock()
+	fn(&st.s)
+	st.mu.Unlock()
+}
+
+func (st *stats) snapshot() Stats {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s := st.s
+	if s.Failures != nil {
+		s.Failures = copyMap(s.Failures)
+	}
+	return s
+}
+
+func ewma(avg, sample float64) float64 {
+	return ewmaAlpha*sample + (1-ewmaAlpha)*avg
+}
+
+// GetStats returns delivery metrics for the default client.
+func GetStats() Stats {
+	client := getDefaultClient()
+	if client == nil {
+		return Stats{}
+	}
+	return client.Stats()
+}
+
+// Stats returns a snapshot of the client's delivery metrics.
+func (c *Client) Stats() Stats {
+	s := c.stats.snapshot()
+	if c.retryBudget != nil {
+		s.RetryBudgetRemaining = c.retryBudget.available(c.config.Clock.Now())
+	}
+	if c.keyLimiter != nil {
+		s.RateLimitedByKey = c.keyLimiter.limited()
+	}
+	return s
+}
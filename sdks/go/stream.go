@@ -0,0 +1,176 @@
+package pulsekit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxStreamBytes = 10 << 20
+	defaultStreamTimeout  = 30 * time.Second
+
+	// streamPlaceholder marks where streamed content is spliced into the
+	// encoded event.
+	streamPlaceholder = "\x00pulsekit-stream\x00"
+	// streamUnsent replaces the streamed value in an event written to the
+	// fallback after a failed send, as the reader cannot be read again.
+	streamUnsent = "[stream not sent]"
+	// streamTruncatedSuffix is appended to streamed content cut off at
+	// MaxStreamBytes.
+	streamTruncatedSuffix = "\n[truncated]"
+)
+
+// metadataStream is a metadata value read from r at send time.
+type metadataStream struct {
+	key string
+	r   io.Reader
+}
+
+// WithMetadataStream adds a metadata value whose contents are read from r
+// and streamed into the request body at send time instead of being held in
+// memory, for large diagnostic attachments such as a log tail. The value is
+// sent as a string of at most Config.MaxStreamBytes bytes, and the whole
+// send is bounded by Config.StreamTimeout. If r is an io.Closer it is
+// closed once sent.
+//
+// Such events bypass the queue and are sent on their own, synchronously, to
+// the single-event endpoint. They are always encoded as JSON and are not
+// retried, since the reader can only be consumed once; if the send fails,
+// the event is written to the fallback (or dropped with
+// DropReasonSendFailed if there is none) with the value replaced by
+// "[stream not sent]".
+func WithMetadataStream(key string, r io.Reader) EventOption {
+	return func(e *Event) {
+		e.stream = &metadataStream{key: key, r: r}
+	}
+}
+
+// sendStream sends an event carrying a metadata stream.
+func (c *Client) sendStream(event Event) {
+	stream := event.stream
+	event.stream = nil
+	c.resolveStack(&event)
+	events := []Event{event}
+	c.enrichAtSend(events)
+	event = events[0]
+	if closer, ok := stream.r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// Encode the event with a placeholder where the stream goes, then
+	// split the encoding around it.
+	event.Metadata = copyMap(event.Metadata)
+	event.Metadata[stream.key] = streamPlaceholder
+	encoded, err := json.Marshal(event)
+	placeholder, _ := json.Marshal(streamPlaceholder)
+	i := bytes.Index(encoded, placeholder)
+	if err != nil || i < 0 {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Failed to marshal %q event: %v\n", event.Type, err)
+		}
+		c.drop(event, DropReasonInvalid)
+		return
+	}
+	prefix := encoded[:i+1]
+	suffix := encoded[i+len(placeholder)-1:]
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeStreamBody(pw, prefix, stream.r, suffix, c.config.MaxStreamBytes))
+	}()
+	defer pr.Close()
+
+	if c.config.DryRun {
+		url := c.config.Endpoint + "/api/v1/events"
+		fmt.Printf("[PulseKit] Dry run: POST %s\n", url)
+		io.Copy(os.Stdout, pr)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.config.StreamTimeout)
+	defer cancel()
+	apiKey, _ := c.apiKeyFor(c.projectFor(&event))
+	err = c.do(ctx, c.config.Endpoint+"/api/v1/events", apiKey, "application/json", pr, "streamed event", func(resp *http.Response) {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Sent streamed %q event, status: %d\n", event.Type, resp.StatusCode)
+		}
+	})
+	if err == nil {
+		return
+	}
+	c.recordFailure(err)
+	event.Metadata[stream.key] = streamUnsent
+	if payloadRejected(err) {
+		if c.config.Debug {
+			fmt.Printf("[PulseKit] Dropping streamed %q event rejected by the server: %v\n", event.Type, err)
+		}
+		c.drop(event, DropReasonRejected)
+		return
+	}
+	c.failEvents([]Event{event})
+}
+
+// writeStreamBody writes prefix, the contents of r escaped as the body of a
+// JSON string (at most max bytes of input), and suffix to w.
+func writeStreamBody(w io.Writer, prefix []byte, r io.Reader, suffix []byte, max int) error {
+	bw := bufio.NewWriter(w)
+	bw.Write(prefix)
+
+	br := bufio.NewReader(io.LimitReader(r, int64(max)+1))
+	read := 0
+	for {
+		ru, size, err := br.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		read += size
+		if read > max {
+			writeJSONStringBody(bw, streamTruncatedSuffix)
+			break
+		}
+		writeJSONRune(bw, ru, size)
+	}
+
+	bw.Write(suffix)
+	return bw.Flush()
+}
+
+func writeJSONStringBody(w *bufio.Writer, s string) {
+	for _, ru := range s {
+		writeJSONRune(w, ru, utf8.RuneLen(ru))
+	}
+}
+
+// writeJSONRune writes ru escaped for use inside a JSON string. Invalid
+// UTF-8 (reported by ReadRune as a one-byte RuneError) becomes U+FFFD.
+func writeJSONRune(w *bufio.Writer, ru rune, size int) {
+	switch {
+	case ru == '"':
+		w.WriteString(`\"`)
+	case ru == '\\':
+		w.WriteString(`\\`)
+	case ru == '\n':
+		w.WriteString(`\n`)
+	case ru == '\r':
+		w.WriteString(`\r`)
+	case ru == '\t':
+		w.WriteString(`\t`)
+	case ru < 0x20, ru == '\u2028', ru == '\u2029':
+		fmt.Fprintf(w, `\u%04x`, ru)
+	case ru == utf8.RuneError && size == 1:
+		w.WriteString(`\ufffd`)
+	default:
+		w.WriteRune(ru)
+	}
+}
@@ -0,0 +1,54 @@
+package pulsekit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamedEventResolvesAsyncStack(t *testing.T) {
+	ts := newTestServer(t)
+	c := newTestClient(t, ts, Config{AsyncStackCapture: true})
+
+	c.CaptureMessage("snapshot", LevelWarning,
+		WithAttachStack(),
+		WithMetadataStream("log", strings.NewReader("line 1\nline \"2\"\n")))
+
+	events := ts.received()
+	if len(events) != 1 {
+		t.Fatalf("received %d events, want 1", len(events))
+	}
+	if len(events[0].Stacktrace) == 0 {
+		t.Error("streamed event has no stack trace")
+	}
+	if got := events[0].Metadata["log"]; got != "line 1\nline \"2\"\n" {
+		t.Errorf("streamed value = %q", got)
+	}
+}
+
+func TestStreamedEventFailureWritesFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+	c, err := NewClient(Config{Endpoint: srv.URL, APIKey: "test-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var buf bytes.Buffer
+	c.fallback = &fallbackWriter{w: &buf}
+
+	c.CaptureMessage("snapshot", LevelInfo, WithMetadataStream("log", strings.NewReader("data")))
+
+	var event Event
+	if err := json.Unmarshal(buf.Bytes(), &event); err != nil {
+		t.Fatalf("fallback output %q: %v", buf.String(), err)
+	}
+	if got := event.Metadata["log"]; got != streamUnsent {
+		t.Errorf("fallback value = %q, want %q", got, streamUnsent)
+	}
+}
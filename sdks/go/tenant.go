@@ -0,0 +1,41 @@
+package pulsekit
+
+import (
+	"context"
+	"fmt"
+)
+
+// WithTenant sets the event's TenantID and a "tenant" tag, so the server can
+// filter, partition and apply quotas per tenant.
+func WithTenant(id string) EventOption {
+	return func(e *Event) {
+		if id == "" {
+			return
+		}
+		e.TenantID = id
+		e.Tags = mergeTags(e.Tags, map[string]string{"tenant": id})
+	}
+}
+
+// ContextWithTenant returns a copy of ctx carrying the tenant ID, for use
+// with CaptureNow when Config.TenantContextKey is not set.
+func ContextWithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantContextKey, id)
+}
+
+// tenantFromContext extracts the tenant ID stored in ctx under
+// Config.TenantContextKey, or ContextWithTenant's key when none is
+// configured. Supported values are strings and fmt.Stringer.
+func (c *Client) tenantFromContext(ctx context.Context) string {
+	key := c.config.TenantContextKey
+	if key == nil {
+		key = tenantContextKey
+	}
+	switch v := ctx.Value(key).(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	}
+	return ""
+}
@@ -0,0 +1,93 @@
+package pulsekit
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// TraceContext identifies the distributed trace an event belongs to.
+type TraceContext struct {
+	TraceID string `json:"trace_id"`
+	SpanID  string `json:"span_id,omitempty"`
+	Sampled bool   `json:"sampled"`
+}
+
+// ParseTraceparent parses a W3C traceparent header
+// ("00-<32 hex trace-id>-<16 hex parent-id>-<2 hex flags>"). It reports false
+// for malformed headers and all-zero IDs.
+func ParseTraceparent(header string) (TraceContext, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || version == "ff" || !isHex(version) {
+		return TraceContext{}, false
+	}
+	// Version 00 has exactly four fields; later versions may append more.
+	if version == "00" && len(parts) != 4 {
+		return TraceContext{}, false
+	}
+	if len(traceID) != 32 || !isHex(traceID) || strings.Trim(traceID, "0") == "" {
+		return TraceContext{}, false
+	}
+	if len(spanID) != 16 || !isHex(spanID) || strings.Trim(spanID, "0") == "" {
+		return TraceContext{}, false
+	}
+	if len(flags) != 2 || !isHex(flags) {
+		return TraceContext{}, false
+	}
+	flagBits, _ := hex.DecodeString(flags)
+	return TraceContext{
+		TraceID: traceID,
+		SpanID:  spanID,
+		Sampled: flagBits[0]&0x01 == 1,
+	}, true
+}
+
+// isHex reports whether s consists only of lowercase hex digits, as W3C
+// Trace Context requires.
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// WithTraceparent sets the event's trace context from a W3C traceparent
+// header value. Malformed headers are ignored.
+func WithTraceparent(header string) EventOption {
+	tc, ok := ParseTraceparent(header)
+	return func(e *Event) {
+		if ok {
+			trace := tc
+			e.Trace = &trace
+		}
+	}
+}
+
+// WithTraceFromRequest sets the event's trace context from the request's
+// traceparent header, if present and valid.
+func WithTraceFromRequest(r *http.Request) EventOption {
+	return WithTraceparent(r.Header.Get("traceparent"))
+}
+
+// SpanLink references a span in another trace that relates to the event,
+// following OpenTelemetry's span-link concept (e.g., the producer spans of
+// messages handled by a batch consumer).
+type SpanLink struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// WithSpanLinks attaches links to spans in other traces.
+func WithSpanLinks(links []SpanLink) EventOption {
+	return func(e *Event) {
+		e.SpanLinks = append(e.SpanLinks, links...)
+	}
+}
@@ -0,0 +1,182 @@
+package pulsekit
+
+import (
+	"sync"
+	"time"
+)
+
+// SpanStatus is the outcome of a transaction or span.
+type SpanStatus string
+
+const (
+	SpanStatusOK        SpanStatus = "ok"
+	SpanStatusError     SpanStatus = "error"
+	SpanStatusCancelled SpanStatus = "cancelled"
+)
+
+// Transaction measures a unit of work and is reported as a "transaction"
+// event when finished.
+type Transaction struct {
+	client *Client
+	name   string
+	op     string
+	start  time.Time
+
+	mu       sync.Mutex
+	tags     map[string]string
+	data     map[string]interface{}
+	spans    []*Span
+	finished bool
+}
+
+// Span measures a child operation within a transaction.
+type Span struct {
+	tx          *Transaction
+	op          string
+	description string
+	start       time.Time
+	end         time.Time
+	status      SpanStatus
+}
+
+// spanPayload is the serialized form of a finished span.
+type spanPayload struct {
+	Op          string     `json:"op"`
+	Description string     `json:"description,omitempty"`
+	Status      SpanStatus `json:"status,omitempty"`
+	StartOffset float64    `json:"start_offset_ms"`
+	DurationMs  float64    `json:"duration_ms"`
+}
+
+// StartTransaction begins a transaction on the default client. If the client
+// is not initialized the returned transaction is a no-op.
+func StartTransaction(name, op string) *Transaction {
+	client := getDefaultClient()
+	if client == nil {
+		return &Transaction{name: name, op: op}
+	}
+	return client.StartTransaction(name, op)
+}
+
+// StartTransaction begins a transaction named name for operation op
+// (e.g., "http.server", "db.query").
+func (c *Client) StartTransaction(name, op string) *Transaction {
+	return &Transaction{
+		client: c,
+		name:   name,
+		op:     op,
+		start:  c.config.Clock.Now(),
+	}
+}
+
+// SetTag sets a tag reported with the transaction event.
+func (t *Transaction) SetTag(key, value string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.tags == nil {
+		t.tags = make(map[string]string)
+	}
+	t.tags[key] = value
+}
+
+// SetData sets a metadata value reported with the transaction event.
+func (t *Transaction) SetData(key string, value interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.data == nil {
+		t.data = make(map[string]interface{})
+	}
+	t.data[key] = value
+}
+
+// SetHTTPStatus records the HTTP response status of the transaction.
+func (t *Transaction) SetHTTPStatus(code int) {
+	t.SetData("http_status", code)
+}
+
+// SetGRPCCode records the gRPC status code of the transaction.
+func (t *Transaction) SetGRPCCode(code uint32) {
+	t.SetData("grpc_code", code)
+}
+
+// StartChild begins a child span of the transaction.
+func (t *Transaction) StartChild(op, description string) *Span {
+	s := &Span{tx: t, op: op, description: description}
+	if t.client != nil {
+		s.start = t.client.config.Clock.Now()
+	}
+	return s
+}
+
+// Finish ends the span with the given status. Spans finished after their
+// transaction are not reported.
+func (s *Span) Finish(status SpanStatus) {
+	t := s.tx
+	if t.client != nil {
+		s.end = t.client.config.Clock.Now()
+	}
+	s.status = status
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.finished {
+		t.spans = append(t.spans, s)
+	}
+}
+
+// Finish ends the transaction with the given status and captures it as a
+// "transaction" event. Calling Finish more than once has no effect.
+func (t *Transaction) Finish(status SpanStatus) {
+	if t.client == nil {
+		return
+	}
+	end := t.client.config.Clock.Now()
+
+	t.mu.Lock()
+	if t.finished {
+		t.mu.Unlock()
+		return
+	}
+	t.finished = true
+
+	metadata := make(map[string]interface{}, len(t.data)+4)
+	for k, v := range t.data {
+		metadata[k] = v
+	}
+	metadata["op"] = t.op
+	metadata["status"] = status
+	metadata["duration_ms"] = durationMs(end.Sub(t.start))
+	if len(t.spans) > 0 {
+		spans := make([]spanPayload, len(t.spans))
+		for i, s := range t.spans {
+			spans[i] = spanPayload{
+				Op:          s.op,
+				Description: s.description,
+				Status:      s.status,
+				StartOffset: durationMs(s.start.Sub(t.start)),
+				DurationMs:  durationMs(s.end.Sub(s.start)),
+			}
+		}
+		metadata["spans"] = spans
+	}
+	tags := copyMap(t.tags)
+	t.mu.Unlock()
+
+	level := LevelInfo
+	if status == SpanStatusError {
+		level = LevelError
+	}
+
+	t.client.Capture(Event{
+		Type:     "transaction",
+		Level:    level,
+		Message:  t.name,
+		Metadata: metadata,
+		Tags:     tags,
+	})
+}
+
+// durationMs converts d to fractional milliseconds.
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
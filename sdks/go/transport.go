@@ -0,0 +1,15 @@
+package pulsekit
+
+import (
+	"net/http"
+	"time"
+)
+
+// newHTTPClient builds the HTTP client used for delivery.
+func newHTTPClient(config Config) *http.Client {
+	client := &http.Client{Timeout: 10 * time.Second}
+	if config.ForceHTTP2 {
+		client.Transport = newHTTP2Transport()
+	}
+	return client
+}
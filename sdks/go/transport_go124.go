@@ -0,0 +1,18 @@
+//go:build go1.24
+
+package pulsekit
+
+import "net/http"
+
+// newHTTP2Transport clones http.DefaultTransport restricted to HTTP/2: TLS
+// endpoints must negotiate h2, and plain http:// endpoints are spoken to in
+// HTTP/2 with prior knowledge (h2c) instead of HTTP/1.1. Connections are
+// still pooled, so concurrent sends multiplex over a single connection to
+// the endpoint.
+func newHTTP2Transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Protocols = new(http.Protocols)
+	t.Protocols.SetHTTP2(true)
+	t.Protocols.SetUnencryptedHTTP2(true)
+	return t
+}
@@ -0,0 +1,71 @@
+//go:build go1.24
+
+package pulsekit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newH2CServer starts a server accepting both HTTP/1.1 and unencrypted
+// HTTP/2, recording the major protocol version of the last request.
+func newH2CServer(t testing.TB, proto *atomic.Int32) *httptest.Server {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto.Store(int32(r.ProtoMajor))
+	}))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestForceHTTP2(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		var proto atomic.Int32
+		srv := newH2CServer(t, &proto)
+		c, err := NewClient(Config{Endpoint: srv.URL, APIKey: "test-key", ForceHTTP2: force})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.CaptureMessage("hello", LevelInfo)
+		c.Close()
+
+		want := int32(1)
+		if force {
+			want = 2
+		}
+		if got := proto.Load(); got != want {
+			t.Errorf("ForceHTTP2=%v: sent over HTTP/%d, want HTTP/%d", force, got, want)
+		}
+	}
+}
+
+// benchmarkParallelSends sends single events from many goroutines at once.
+func benchmarkParallelSends(b *testing.B, forceHTTP2 bool) {
+	var proto atomic.Int32
+	srv := newH2CServer(b, &proto)
+	c, err := NewClient(Config{Endpoint: srv.URL, APIKey: "test-key", ForceHTTP2: forceHTTP2})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+	event := sizedEvent(0)
+	c.prepare(&event)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.sendEvents(context.Background(), []Event{event})
+		}
+	})
+}
+
+func BenchmarkSendHTTP1(b *testing.B) { benchmarkParallelSends(b, false) }
+
+func BenchmarkSendHTTP2(b *testing.B) { benchmarkParallelSends(b, true) }
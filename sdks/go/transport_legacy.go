@@ -0,0 +1,13 @@
+//go:build !go1.24
+
+package pulsekit
+
+import "net/http"
+
+// newHTTP2Transport clones http.DefaultTransport. Restricting a transport to
+// HTTP/2, and HTTP/2 over plain http:// endpoints, need http.Protocols from
+// Go 1.24; before that, HTTP/2 is negotiated with TLS endpoints as by the
+// default transport.
+func newHTTP2Transport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
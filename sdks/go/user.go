@@ -0,0 +1,80 @@
+package pulsekit
+
+import "context"
+
+// User identifies the user affected by an event.
+type User struct {
+	ID        string `json:"id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Username  string `json:"username,omitempty"`
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// UserProvider is implemented by application user types that can describe
+// themselves to PulseKit. Values stored under Config.UserContextKey may
+// implement it.
+type UserProvider interface {
+	PulseKitUser() User
+}
+
+// WithUser sets the event's user.
+func WithUser(user User) EventOption {
+	return func(e *Event) {
+		e.User = &user
+	}
+}
+
+// WithUserFromContext sets the event's user from ctx when it is queued. The
+// user is read from Config.UserContextKey, or from ContextWithUser when no
+// key is configured.
+func WithUserFromContext(ctx context.Context) EventOption {
+	return func(e *Event) {
+		e.userCtx = ctx
+	}
+}
+
+// ContextWithUser returns a copy of ctx carrying user, for use with
+// WithUserFromContext and CaptureNow when Config.UserContextKey is not set.
+func ContextWithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
+}
+
+// userFromContext extracts the user stored in ctx. Supported values are
+// User, *User, UserProvider and a string user ID.
+func (c *Client) userFromContext(ctx context.Context) *User {
+	key := c.config.UserContextKey
+	if key == nil {
+		key = userContextKey
+	}
+	switch v := ctx.Value(key).(type) {
+	case User:
+		return &v
+	case *User:
+		if v == nil {
+			return nil
+		}
+		u := *v
+		return &u
+	case UserProvider:
+		u := v.PulseKitUser()
+		return &u
+	case string:
+		if v == "" {
+			return nil
+		}
+		return &User{ID: v}
+	}
+	return nil
+}
+
+// applyUserContext resolves a pending WithUserFromContext. An explicitly set
+// user is kept.
+func (c *Client) applyUserContext(event *Event) {
+	if event.userCtx == nil {
+		return
+	}
+	if event.User == nil {
+		event.User = c.userFromContext(event.userCtx)
+	}
+	event.userCtx = nil
+}
@@ -0,0 +1,50 @@
+package pulsekit
+
+import "strings"
+
+// FieldError is a single failed field in a validation event.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// CaptureValidationErrors captures field validation failures using the
+// default client.
+func CaptureValidationErrors(errs map[string]string, opts ...EventOption) {
+	client := getDefaultClient()
+	if client == nil {
+		return
+	}
+	client.CaptureValidationErrors(errs, opts...)
+}
+
+// CaptureValidationErrors captures all field errors of a failed validation
+// as one "validation" event at warning level. The field errors are sorted by
+// field under the "field_errors" metadata key, and the event is fingerprinted
+// by the set of failing fields, so the same form failing on the same fields
+// groups together regardless of the messages.
+func (c *Client) CaptureValidationErrors(errs map[string]string, opts ...EventOption) {
+	if len(errs) == 0 {
+		return
+	}
+
+	fields := sortedKeys(errs)
+	fieldErrors := make([]FieldError, len(fields))
+	for i, field := range fields {
+		fieldErrors[i] = FieldError{Field: field, Message: errs[field]}
+	}
+
+	event := Event{
+		Type:        "validation",
+		Level:       LevelWarning,
+		Message:     "Validation failed: " + strings.Join(fields, ", "),
+		Metadata:    map[string]interface{}{"field_errors": fieldErrors},
+		Fingerprint: hashFingerprint(append([]string{"validation"}, fields...)...),
+	}
+
+	for _, opt := range opts {
+		opt(&event)
+	}
+
+	c.enqueue(event)
+}
@@ -0,0 +1,10 @@
+module github.com/pulsekit/go/yaml
+
+go 1.21
+
+require (
+	github.com/pulsekit/go v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/pulsekit/go => ../
@@ -0,0 +1,52 @@
+// Package pulsekityaml loads PulseKit configuration from YAML files. It is a
+// separate module so the core SDK does not depend on a YAML parser.
+//
+// Documents use the same snake_case keys as pulsekit.ParseConfig and are
+// decoded by it, so environment references, durations and unknown keys are
+// handled the same way:
+//
+//	endpoint: https://your-pulsekit-instance.com
+//	api_key: ${PULSEKIT_API_KEY}
+//	flush_interval: 5s
+package pulsekityaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	pulsekit "github.com/pulsekit/go"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseConfig decodes a YAML configuration document into a Config. See
+// pulsekit.ParseConfig for the supported keys and environment expansion.
+func ParseConfig(data []byte) (pulsekit.Config, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return pulsekit.Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	// Mappings with composite keys have no JSON form and fail here.
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return pulsekit.Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+	return pulsekit.ParseConfig(encoded)
+}
+
+// InitFromFile reads a YAML configuration file, decodes it with ParseConfig
+// and initializes the default client with the result.
+func InitFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config: %w", err)
+	}
+	config, err := ParseConfig(data)
+	if err != nil {
+		return err
+	}
+	return pulsekit.Init(config)
+}
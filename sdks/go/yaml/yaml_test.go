@@ -0,0 +1,61 @@
+package pulsekityaml
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	pulsekit "github.com/pulsekit/go"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Setenv("PK_KEY", `ab"c`)
+
+	config, err := ParseConfig([]byte(`
+endpoint: https://pulsekit.example.com
+api_key: ${PK_KEY}
+flush_interval: 5s
+batch_size: 50
+projects:
+  billing: billing-key
+tags_by_level:
+  error:
+    team: core
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Endpoint != "https://pulsekit.example.com" || config.APIKey != `ab"c` {
+		t.Errorf("Endpoint, APIKey = %q, %q", config.Endpoint, config.APIKey)
+	}
+	if config.FlushInterval != 5*time.Second || config.BatchSize != 50 {
+		t.Errorf("FlushInterval, BatchSize = %v, %d", config.FlushInterval, config.BatchSize)
+	}
+	if config.Projects["billing"] != "billing-key" {
+		t.Errorf("Projects = %v", config.Projects)
+	}
+	if config.TagsByLevel[pulsekit.LevelError]["team"] != "core" {
+		t.Errorf("TagsByLevel = %v", config.TagsByLevel)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "syntax", data: "api_key: [", want: "invalid config"},
+		{name: "unknown key", data: "api_keys: key", want: "unknown field"},
+		{name: "composite key", data: "projects:\n  [a, b]: key", want: "invalid config"},
+		{name: "not a mapping", data: "- api_key", want: "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParseConfig(%q) error = %v, want it to contain %q", tt.data, err, tt.want)
+			}
+		})
+	}
+}